@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "2.0.0"
+      source  = "hashicorp.com/flowdeskmarkets/clickhouse"
+    }
+  }
+}
+
+
+data "clickhouse_db" "system" {
+  name = "system"
+}
+
+output "system_db_engine" {
+  value = data.clickhouse_db.system.engine_full
+}
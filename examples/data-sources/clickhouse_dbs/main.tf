@@ -13,3 +13,23 @@ data "clickhouse_dbs" "this" {}
 output "all_dbs" {
   value = data.clickhouse_dbs.this.dbs
 }
+
+data "clickhouse_dbs" "analytics" {
+  name_regex     = "^analytics_"
+  engines        = ["Atomic", "Replicated"]
+  exclude_system = true
+}
+
+output "analytics_dbs" {
+  value = data.clickhouse_dbs.analytics.names
+}
+
+data "clickhouse_dbs" "second_page" {
+  exclude_system = true
+  limit          = 20
+  offset         = 20
+}
+
+output "second_page_dbs" {
+  value = data.clickhouse_dbs.second_page.names
+}
@@ -0,0 +1,9 @@
+output "normalized_query" {
+  # "select event_date, count() from events group by event_date"
+  value = provider::clickhouse::normalize_query(<<-EOT
+    SELECT event_date, count()
+    FROM events
+    GROUP BY event_date
+  EOT
+  )
+}
@@ -0,0 +1,4 @@
+output "events_database" {
+  # "analytics" for the ID "cluster:analytics:events"
+  value = provider::clickhouse::parse_table_id(clickhouse_table.events.id).database
+}
@@ -0,0 +1,3 @@
+output "select_events" {
+  value = "SELECT * FROM ${provider::clickhouse::quote_identifier("my-database")}.${provider::clickhouse::quote_identifier("events")}"
+}
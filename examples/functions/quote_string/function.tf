@@ -0,0 +1,11 @@
+resource "clickhouse_table" "queue" {
+  database      = "analytics"
+  name          = "events_queue"
+  engine        = "Kafka"
+  engine_params = [provider::clickhouse::quote_string(var.broker), provider::clickhouse::quote_string("events"), "'consumer'", "'JSONEachRow'"]
+
+  columns {
+    name = "payload"
+    type = "String"
+  }
+}
@@ -0,0 +1,16 @@
+resource "clickhouse_table" "replicated_table" {
+  database = "analytics"
+  name     = "events"
+  cluster  = "cluster"
+  engine   = "ReplicatedMergeTree"
+  engine_params = [
+    provider::clickhouse::quote_string(provider::clickhouse::replicated_path("/clickhouse/{installation}", "analytics", "events")),
+    "'{replica}'",
+  ]
+  order_by = ["event_date"]
+
+  columns {
+    name = "event_date"
+    type = "Date"
+  }
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/provider"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 )
 
 // Run "go generate" to format example terraform files and generate the docs for the registry/website
@@ -32,14 +34,19 @@ func main() {
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		Debug: debugMode,
-
-		// TODO: update this string with the full name of your provider as used in your configs
-		ProviderAddr: "registry.terraform.io/flowdeskmarkets/clickhouse",
+	ctx := context.Background()
+	muxServer, err := provider.MuxServer(ctx, provider.New(version)(), version)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		ProviderFunc: provider.New(version),
+	var serveOpts []tf6server.ServeOpt
+	if debugMode {
+		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
 	}
 
-	plugin.Serve(opts)
+	err = tf6server.Serve("registry.terraform.io/flowdeskmarkets/clickhouse", muxServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
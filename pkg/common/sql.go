@@ -0,0 +1,113 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	stringLiteralReplacer = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	identifierReplacer    = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+)
+
+// QuoteString returns the value as a Clickhouse string literal, e.g. `it's` becomes `'it\'s'`
+func QuoteString(value string) string {
+	return "'" + stringLiteralReplacer.Replace(value) + "'"
+}
+
+// QuoteIdentifier returns the name as a Clickhouse backquoted identifier, e.g. `article.id`
+// becomes "`article.id`"
+func QuoteIdentifier(name string) string {
+	return "`" + identifierReplacer.Replace(name) + "`"
+}
+
+// QuoteQualifiedName returns the quoted name of an object of a database, e.g. "`analytics`.`events`"
+func QuoteQualifiedName(database string, name string) string {
+	return QuoteIdentifier(database) + "." + QuoteIdentifier(name)
+}
+
+// clusterMacroRegexp matches the macros of the server configuration given as cluster, e.g. '{cluster}'
+var clusterMacroRegexp = regexp.MustCompile(`^'\{[A-Za-z0-9_]+\}'$`)
+
+// QuoteCluster returns the cluster name as an identifier. A macro like '{cluster}' is kept as is
+// and the other names already quoted as a string literal or an identifier are quoted again
+// without their quotes, so that they can't close the quotes early.
+func QuoteCluster(cluster string) string {
+	if clusterMacroRegexp.MatchString(cluster) {
+		return cluster
+	}
+	if len(cluster) >= 2 {
+		first, last := cluster[0], cluster[len(cluster)-1]
+		if first == last && first == '\'' {
+			return QuoteString(cluster[1 : len(cluster)-1])
+		}
+		if first == last && first == '`' {
+			return QuoteIdentifier(cluster[1 : len(cluster)-1])
+		}
+	}
+	return QuoteIdentifier(cluster)
+}
+
+// Statement builds a SQL statement from raw SQL, quoted identifiers and string literals, joined
+// by single spaces. Raw SQL is reserved to keywords and expressions written in the configuration
+// (types, defaults, engines...), every name and text value goes through the quoting methods.
+type Statement struct {
+	parts []string
+}
+
+// NewStatement starts a statement with raw SQL, e.g. `NewStatement("DROP TABLE IF EXISTS")`
+func NewStatement(sql ...string) *Statement {
+	return (&Statement{}).Raw(sql...)
+}
+
+// Raw appends raw SQL, empty parts are skipped
+func (s *Statement) Raw(sql ...string) *Statement {
+	for _, part := range sql {
+		if part = strings.TrimSpace(part); part != "" {
+			s.parts = append(s.parts, part)
+		}
+	}
+	return s
+}
+
+// Identifier appends a quoted identifier
+func (s *Statement) Identifier(name string) *Statement {
+	return s.Raw(QuoteIdentifier(name))
+}
+
+// Identifiers appends a comma separated list of quoted identifiers
+func (s *Statement) Identifiers(names []string) *Statement {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, QuoteIdentifier(name))
+	}
+	return s.Raw(strings.Join(quoted, ", "))
+}
+
+// QualifiedName appends the quoted name of an object of a database
+func (s *Statement) QualifiedName(database string, name string) *Statement {
+	return s.Raw(QuoteQualifiedName(database, name))
+}
+
+// Literal appends a string literal
+func (s *Statement) Literal(value string) *Statement {
+	return s.Raw(QuoteString(value))
+}
+
+// OnCluster appends the ON CLUSTER clause when a cluster is set
+func (s *Statement) OnCluster(cluster string) *Statement {
+	return s.Raw(GetClusterStatement(cluster))
+}
+
+// Comment appends the COMMENT clause when the comment is set
+func (s *Statement) Comment(comment string) *Statement {
+	if comment == "" {
+		return s
+	}
+	return s.Raw("COMMENT").Literal(comment)
+}
+
+// SQL returns the statement
+func (s *Statement) SQL() string {
+	return strings.Join(s.parts, " ")
+}
@@ -0,0 +1,64 @@
+package common
+
+import "testing"
+
+func TestQuoteString(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "", expected: "''"},
+		{value: "Raw events", expected: "'Raw events'"},
+		{value: "it's", expected: `'it\'s'`},
+		{value: `C:\data`, expected: `'C:\\data'`},
+		{value: `x\'; DROP TABLE events; --`, expected: `'x\\\'; DROP TABLE events; --'`},
+	}
+
+	for _, tt := range testCases {
+		if quoted := QuoteString(tt.value); quoted != tt.expected {
+			t.Errorf("QuoteString(%q) = %s, expected %s", tt.value, quoted, tt.expected)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "events", expected: "`events`"},
+		{name: "article.id", expected: "`article.id`"},
+		{name: "my-user", expected: "`my-user`"},
+		{name: "x` ; DROP TABLE events; --", expected: "`x\\` ; DROP TABLE events; --`"},
+		{name: `x\`, expected: "`x\\\\`"},
+	}
+
+	for _, tt := range testCases {
+		if quoted := QuoteIdentifier(tt.name); quoted != tt.expected {
+			t.Errorf("QuoteIdentifier(%q) = %s, expected %s", tt.name, quoted, tt.expected)
+		}
+	}
+}
+
+func TestQuoteCluster(t *testing.T) {
+	testCases := []struct {
+		cluster  string
+		expected string
+	}{
+		{cluster: "cluster", expected: "`cluster`"},
+		{cluster: "'{cluster}'", expected: "'{cluster}'"},
+		{cluster: "'{cluster_2}'", expected: "'{cluster_2}'"},
+		{cluster: "'cluster'", expected: "'cluster'"},
+		{cluster: "`cluster`", expected: "`cluster`"},
+		{cluster: "'x' ; DROP TABLE events; --'", expected: `'x\' ; DROP TABLE events; --'`},
+		{cluster: "'{cluster}' ; DROP TABLE events; '{cluster}'", expected: `'{cluster}\' ; DROP TABLE events; \'{cluster}'`},
+		{cluster: "`x` ; DROP TABLE events; `x`", expected: "`x\\` ; DROP TABLE events; \\`x`"},
+		{cluster: "'", expected: "`'`"},
+	}
+
+	for _, tt := range testCases {
+		if quoted := QuoteCluster(tt.cluster); quoted != tt.expected {
+			t.Errorf("QuoteCluster(%q) = %s, expected %s", tt.cluster, quoted, tt.expected)
+		}
+	}
+}
@@ -9,9 +9,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// GetClusterStatement returns the ON CLUSTER clause of the cluster, see QuoteCluster
 func GetClusterStatement(cluster string) (clusterStatement string) {
 	if cluster != "" {
-		return fmt.Sprintf("ON CLUSTER %s", cluster)
+		return "ON CLUSTER " + QuoteCluster(cluster)
 	}
 	return ""
 }
@@ -52,19 +53,47 @@ func NormalizeQuery(query string) string {
 	return query
 }
 
-func GetCreateStatement(resourceType string) string {
+const (
+	CreateModeCreate      = "create"
+	CreateModeOrReplace   = "create_or_replace"
+	CreateModeIfNotExists = "create_if_not_exists"
+	// CreateModeAdopt adopts an existing object identical to the configuration, and fails if it differs
+	CreateModeAdopt = "adopt"
+)
+
+// CreateModes lists the create modes supported by each resource type, databases can't be replaced
+var CreateModes = map[string][]string{
+	"database": {CreateModeCreate, CreateModeIfNotExists, CreateModeAdopt},
+	"table":    {CreateModeCreate, CreateModeOrReplace, CreateModeIfNotExists, CreateModeAdopt},
+	"view":     {CreateModeCreate, CreateModeOrReplace, CreateModeIfNotExists, CreateModeAdopt},
+}
+
+// GetCreateStatement returns the CREATE statement for the resource type in the given create mode.
+// When no mode is provided, it is read from the deprecated TF_VAR_CREATE_OR_REPLACE and
+// TF_VAR_CREATE_IF_NOT_EXISTS env vars. OR REPLACE is ignored for databases and materialized views,
+// which can't be replaced.
+func GetCreateStatement(resourceType string, createMode string) string {
 	resourceType = strings.ToUpper(resourceType)
-	isDatabase := resourceType == "DATABASE"
+	isReplaceable := resourceType != "DATABASE" && resourceType != "MATERIALIZED VIEW"
 
-	if IsEnvTrue("TF_VAR_CREATE_OR_REPLACE") && !isDatabase {
-		return fmt.Sprintf("CREATE OR REPLACE %s", resourceType)
+	if createMode == "" {
+		createMode = CreateModeCreate
+		if IsEnvTrue("TF_VAR_CREATE_IF_NOT_EXISTS") {
+			createMode = CreateModeIfNotExists
+		}
+		if IsEnvTrue("TF_VAR_CREATE_OR_REPLACE") && isReplaceable {
+			createMode = CreateModeOrReplace
+		}
 	}
 
-	if IsEnvTrue("TF_VAR_CREATE_IF_NOT_EXISTS") {
+	switch {
+	case createMode == CreateModeOrReplace && isReplaceable:
+		return fmt.Sprintf("CREATE OR REPLACE %s", resourceType)
+	case createMode == CreateModeIfNotExists:
 		return fmt.Sprintf("CREATE %s IF NOT EXISTS", resourceType)
+	default:
+		return fmt.Sprintf("CREATE %s", resourceType)
 	}
-
-	return fmt.Sprintf("CREATE %s", resourceType)
 }
 
 func IsEnvTrue(envVar string) bool {
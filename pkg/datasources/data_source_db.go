@@ -0,0 +1,122 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &dbDataSource{}
+
+// dbDataSource reads a single database, like the clickhouse_db resource
+type dbDataSource struct {
+	client sdk.ClickhouseClient
+}
+
+type dbDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Engine       types.String `tfsdk:"engine"`
+	EngineFull   types.String `tfsdk:"engine_full"`
+	DataPath     types.String `tfsdk:"data_path"`
+	MetadataPath types.String `tfsdk:"metadata_path"`
+	UUID         types.String `tfsdk:"uuid"`
+	Comment      types.String `tfsdk:"comment"`
+}
+
+func NewDbDataSource() datasource.DataSource {
+	return &dbDataSource{}
+}
+
+func (d *dbDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_db"
+}
+
+func (d *dbDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Datasource to retrieve a database of the clickhouse instance",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "DB Name",
+				Required:            true,
+			},
+			"engine": schema.StringAttribute{
+				MarkdownDescription: "DB Engine",
+				Computed:            true,
+			},
+			"engine_full": schema.StringAttribute{
+				MarkdownDescription: "DB Engine with its arguments and settings, the passwords being hidden by the server",
+				Computed:            true,
+			},
+			"data_path": schema.StringAttribute{
+				MarkdownDescription: "DB Path",
+				Computed:            true,
+			},
+			"metadata_path": schema.StringAttribute{
+				MarkdownDescription: "Metadata Path",
+				Computed:            true,
+			},
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "DB UUID",
+				Computed:            true,
+			},
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "Database comment",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *dbDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(sdk.ClickhouseClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected sdk.ClickhouseClient, got %T.", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *dbDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dbDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = sdk.WithOrigin(ctx, sdk.Origin{ResourceType: "clickhouse_db", ResourceName: config.Name.ValueString(), Operation: "read"})
+
+	chDatabase, err := d.client.GetDatabase(ctx, config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read database", err.Error())
+		return
+	}
+	if chDatabase == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Database not found", fmt.Sprintf("The database %s doesn't exist.", config.Name.ValueString()))
+		return
+	}
+
+	state := dbDataSourceModel{
+		ID:           types.StringValue(chDatabase.Name),
+		Name:         types.StringValue(chDatabase.Name),
+		Engine:       types.StringValue(chDatabase.Engine),
+		EngineFull:   types.StringValue(chDatabase.EngineFull),
+		DataPath:     types.StringValue(chDatabase.DataPath),
+		MetadataPath: types.StringValue(chDatabase.MetadataPath),
+		UUID:         types.StringValue(chDatabase.UUID),
+		Comment:      types.StringValue(chDatabase.Comment),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,43 @@
+package datasources_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/testutils"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDb(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { testutils.TestAccPreCheck(t) },
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDb,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.clickhouse_db.this", "id", databaseName),
+					resource.TestCheckResourceAttr("data.clickhouse_db.this", "name", databaseName),
+					resource.TestCheckResourceAttrSet("data.clickhouse_db.this", "engine"),
+					resource.TestCheckResourceAttrSet("data.clickhouse_db.this", "metadata_path"),
+					resource.TestCheckResourceAttrSet("data.clickhouse_db.this", "uuid"),
+				),
+			},
+			{
+				Config:      testAccDataSourceDbMissing,
+				ExpectError: regexp.MustCompile("Database not found"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceDb = `
+data "clickhouse_db" "this" {
+  name = "system"
+}`
+
+const testAccDataSourceDbMissing = `
+data "clickhouse_db" "missing" {
+  name = "missing_database"
+}`
@@ -2,55 +2,126 @@ package datasources
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"strings"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func DataSourceDbs() *schema.Resource {
-	return &schema.Resource{
-		// This description is used by the documentation generator and the language server.
-		Description: "Datasource to retrieve all databases set in clickhouse instance",
+var _ datasource.DataSourceWithConfigure = &dbsDataSource{}
 
-		ReadContext: dataSourceDbsRead,
+// dbsDataSource is served by the plugin framework provider
+type dbsDataSource struct {
+	client sdk.ClickhouseClient
+}
 
-		Schema: map[string]*schema.Schema{
-			"dbs": {
-				Type:     schema.TypeList,
+type dbsDataSourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	NameRegex       types.String   `tfsdk:"name_regex"`
+	Engines         []types.String `tfsdk:"engines"`
+	ExcludeSystem   types.Bool     `tfsdk:"exclude_system"`
+	CommentContains types.String   `tfsdk:"comment_contains"`
+	Limit           types.Int64    `tfsdk:"limit"`
+	Offset          types.Int64    `tfsdk:"offset"`
+	Names           []types.String `tfsdk:"names"`
+	Dbs             []dbsItemModel `tfsdk:"dbs"`
+}
+
+type dbsItemModel struct {
+	Name         types.String `tfsdk:"name"`
+	Engine       types.String `tfsdk:"engine"`
+	DataPath     types.String `tfsdk:"data_path"`
+	MetadataPath types.String `tfsdk:"metadata_path"`
+	Uuid         types.String `tfsdk:"uuid"`
+	Comment      types.String `tfsdk:"comment"`
+}
+
+func NewDbsDataSource() datasource.DataSource {
+	return &dbsDataSource{}
+}
+
+func (d *dbsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dbs"
+}
+
+func (d *dbsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Datasource to retrieve the databases set in clickhouse instance, optionally filtered",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
 				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Description: "DB Name",
-							Type:        schema.TypeString,
-							Computed:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only read the databases whose name matches this regular expression, using the [re2 syntax](https://github.com/google/re2/wiki/Syntax) of ClickHouse `match`",
+				Optional:            true,
+			},
+			"engines": schema.ListAttribute{
+				MarkdownDescription: "Only read the databases with one of these engines, e.g. `[\"Atomic\", \"Replicated\"]`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"exclude_system": schema.BoolAttribute{
+				MarkdownDescription: fmt.Sprintf("Skip the databases of the server itself: `%s`", strings.Join(models.SystemDatabases, "`, `")),
+				Optional:            true,
+			},
+			"comment_contains": schema.StringAttribute{
+				MarkdownDescription: "Only read the databases whose comment contains this string, case sensitive",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of databases read, the databases being ordered by name. All of them are read when not set",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"offset": schema.Int64Attribute{
+				MarkdownDescription: "Number of databases skipped before reading them, e.g. `limit * page` to read the page `page` of `limit` databases",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"names": schema.ListAttribute{
+				MarkdownDescription: "Names of the databases read, sorted, e.g. for `for_each = toset(data.clickhouse_dbs.this.names)`",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"dbs": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "DB Name",
+							Computed:            true,
 						},
-						"engine": {
-							Description: "DB Engine",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"engine": schema.StringAttribute{
+							MarkdownDescription: "DB Engine",
+							Computed:            true,
 						},
-						"data_path": {
-							Description: "DB Path",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"data_path": schema.StringAttribute{
+							MarkdownDescription: "DB Path",
+							Computed:            true,
 						},
-						"metadata_path": {
-							Description: "Metadata Path",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"metadata_path": schema.StringAttribute{
+							MarkdownDescription: "Metadata Path",
+							Computed:            true,
 						},
-						"uuid": {
-							Description: "Metadata Path",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "Metadata Path",
+							Computed:            true,
 						},
-						"comment": {
-							Description: "Database comment, it will be codified in a json along with come metadata information (like cluster name in case of clustering)",
-							Type:        schema.TypeString,
-							Computed:    true,
+						"comment": schema.StringAttribute{
+							MarkdownDescription: "Database comment, it will be codified in a json along with come metadata information (like cluster name in case of clustering)",
+							Computed:            true,
 						},
 					},
 				},
@@ -59,35 +130,56 @@ func DataSourceDbs() *schema.Resource {
 	}
 }
 
-func dataSourceDbsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	c := meta.(*sdk.Client)
-	var diags diag.Diagnostics
-
-	rows, err := c.Conn.Query(ctx, "SELECT name, engine, data_path, metadata_path, uuid, comment FROM system.databases")
-	if err != nil {
-		return diag.FromErr(err)
+func (d *dbsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
 	}
+	client, ok := req.ProviderData.(sdk.ClickhouseClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected sdk.ClickhouseClient, got %T.", req.ProviderData))
+		return
+	}
+	d.client = client
+}
 
-	var dbResources []map[string]interface{}
+func (d *dbsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state dbsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = sdk.WithOrigin(ctx, sdk.Origin{ResourceType: "clickhouse_dbs", Operation: "read"})
 
-	for i := 0; rows.Next(); i++ {
-		var chDatabase CHDatabase
-		if err := rows.ScanStruct(&chDatabase); err != nil {
-			log.Fatal(err)
-		}
-		dbResource := map[string]interface{}{
-			"name":          chDatabase.Name,
-			"engine":        chDatabase.Engine,
-			"data_path":     chDatabase.DataPath,
-			"metadata_path": chDatabase.MetadataPath,
-			"uuid":          chDatabase.Uuid,
-			"comment":       chDatabase.Comment,
-		}
-		dbResources = append(dbResources, dbResource)
+	filter := models.DatabaseFilter{
+		NameRegex:       state.NameRegex.ValueString(),
+		ExcludeSystem:   state.ExcludeSystem.ValueBool(),
+		CommentContains: state.CommentContains.ValueString(),
+		Limit:           uint64(state.Limit.ValueInt64()),
+		Offset:          uint64(state.Offset.ValueInt64()),
+	}
+	for _, engine := range state.Engines {
+		filter.Engines = append(filter.Engines, engine.ValueString())
 	}
-	if err := d.Set("dbs", dbResources); err != nil {
-		return diag.FromErr(err)
+	chDatabases, err := d.client.GetDatabases(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read databases", err.Error())
+		return
+	}
+
+	state.ID = types.StringValue("databases_read")
+	state.Names = []types.String{}
+	state.Dbs = []dbsItemModel{}
+	for _, chDatabase := range chDatabases {
+		state.Names = append(state.Names, types.StringValue(chDatabase.Name))
+		state.Dbs = append(state.Dbs, dbsItemModel{
+			Name:         types.StringValue(chDatabase.Name),
+			Engine:       types.StringValue(chDatabase.Engine),
+			DataPath:     types.StringValue(chDatabase.DataPath),
+			MetadataPath: types.StringValue(chDatabase.MetadataPath),
+			Uuid:         types.StringValue(chDatabase.UUID),
+			Comment:      types.StringValue(chDatabase.Comment),
+		})
 	}
-	d.SetId("databases_read")
-	return diags
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -18,7 +18,7 @@ func TestAccDataSourceDbs(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() { testutils.TestAccPreCheck(t) },
 		// ProviderFactories: providerFactories,
-		Providers: testutils.Provider(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccDataSourceDbs,
@@ -26,6 +26,16 @@ func TestAccDataSourceDbs(t *testing.T) {
 					checkDatabases(),
 				),
 			},
+			{
+				Config: testAccDataSourceDbsFiltered,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.clickhouse_dbs.system", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.clickhouse_dbs.system", "names.0", "system"),
+					resource.TestCheckResourceAttr("data.clickhouse_dbs.none", "names.#", "0"),
+					resource.TestCheckResourceAttr("data.clickhouse_dbs.page", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.clickhouse_dbs.page", "names.0", "information_schema"),
+				),
+			},
 		},
 	})
 }
@@ -90,3 +100,19 @@ func checkDatabases() resource.TestCheckFunc {
 
 const testAccDataSourceDbs = `
 data "clickhouse_dbs" "this" {}`
+
+const testAccDataSourceDbsFiltered = `
+data "clickhouse_dbs" "system" {
+  name_regex = "^sys"
+}
+
+data "clickhouse_dbs" "none" {
+  name_regex     = "^sys"
+  exclude_system = true
+}
+
+data "clickhouse_dbs" "page" {
+  name_regex = "^(INFORMATION_SCHEMA|information_schema|system)$"
+  limit      = 1
+  offset     = 1
+}`
@@ -0,0 +1,106 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction calls the function with string arguments, the way Terraform does
+func runFunction(t *testing.T, f function.Function, args ...string) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+
+	definitionResp := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definitionResp)
+	result, funcErr := definitionResp.Definition.Return.NewResultData(ctx)
+	if funcErr != nil {
+		t.Fatalf("NewResultData() error = %v", funcErr)
+	}
+
+	var values []attr.Value
+	for _, arg := range args {
+		values = append(values, types.StringValue(arg))
+	}
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(values)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestStringFunctions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		function    function.Function
+		args        []string
+		expected    string
+		expectError bool
+	}{
+		{name: "quote_string", function: NewQuoteStringFunction(), args: []string{"kafka:9092"}, expected: `'kafka:9092'`},
+		{name: "quote_string escapes quotes", function: NewQuoteStringFunction(), args: []string{`it's`}, expected: `'it\'s'`},
+		{name: "quote_string escapes backslashes", function: NewQuoteStringFunction(), args: []string{`a\'b`}, expected: `'a\\\'b'`},
+		{name: "quote_identifier", function: NewQuoteIdentifierFunction(), args: []string{"article.id"}, expected: "`article.id`"},
+		{name: "quote_identifier escapes backquotes", function: NewQuoteIdentifierFunction(), args: []string{"a`b"}, expected: "`a\\`b`"},
+		{name: "normalize_query", function: NewNormalizeQueryFunction(), args: []string{"SELECT *\n  FROM   events"}, expected: "select * from events"},
+		{name: "replicated_path", function: NewReplicatedPathFunction(), args: []string{"/clickhouse/{installation}/", "analytics", "events"}, expected: "/clickhouse/{installation}/tables/{shard}/analytics/events"},
+		{name: "replicated_path relative prefix", function: NewReplicatedPathFunction(), args: []string{"clickhouse", "analytics", "events"}, expectError: true},
+		{name: "replicated_path empty table", function: NewReplicatedPathFunction(), args: []string{"/clickhouse", "analytics", ""}, expectError: true},
+		{name: "replicated_path database with a slash", function: NewReplicatedPathFunction(), args: []string{"/clickhouse", "a/b", "events"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, funcErr := runFunction(t, tc.function, tc.args...)
+			if tc.expectError {
+				if funcErr == nil {
+					t.Fatalf("expected an error, got %v", value)
+				}
+				return
+			}
+			if funcErr != nil {
+				t.Fatalf("unexpected error: %v", funcErr)
+			}
+			if got := value.(types.String).ValueString(); got != tc.expected {
+				t.Errorf("got %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseTableID(t *testing.T) {
+	testCases := []struct {
+		id          string
+		expected    map[string]string
+		expectError bool
+	}{
+		{id: "cluster:analytics:events", expected: map[string]string{"cluster": "cluster", "database": "analytics", "name": "events"}},
+		{id: ":analytics:events", expected: map[string]string{"cluster": "", "database": "analytics", "name": "events"}},
+		{id: "analytics:events", expected: map[string]string{"cluster": "", "database": "analytics", "name": "events"}},
+		{id: "events", expectError: true},
+		{id: "a:b:c:d", expectError: true},
+		{id: "analytics:", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			value, funcErr := runFunction(t, NewParseTableIDFunction(), tc.id)
+			if tc.expectError {
+				if funcErr == nil {
+					t.Fatalf("expected an error, got %v", value)
+				}
+				return
+			}
+			if funcErr != nil {
+				t.Fatalf("unexpected error: %v", funcErr)
+			}
+			attributes := value.(types.Object).Attributes()
+			for name, expected := range tc.expected {
+				if got := attributes[name].(types.String).ValueString(); got != expected {
+					t.Errorf("%s = %q, expected %q", name, got, expected)
+				}
+			}
+		})
+	}
+}
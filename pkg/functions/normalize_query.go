@@ -0,0 +1,43 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &normalizeQueryFunction{}
+
+type normalizeQueryFunction struct{}
+
+func NewNormalizeQueryFunction() function.Function {
+	return &normalizeQueryFunction{}
+}
+
+func (f *normalizeQueryFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_query"
+}
+
+func (f *normalizeQueryFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalizes a query",
+		MarkdownDescription: "Returns the query lower cased with its whitespaces collapsed, as compared by `clickhouse_view` to detect changes of its `query`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "query",
+				MarkdownDescription: "Query to normalize",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *normalizeQueryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var query string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &query))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, common.NormalizeQuery(query)))
+}
@@ -0,0 +1,74 @@
+package functions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &parseTableIDFunction{}
+
+type parseTableIDFunction struct{}
+
+type tableIDModel struct {
+	Cluster  string `tfsdk:"cluster"`
+	Database string `tfsdk:"database"`
+	Name     string `tfsdk:"name"`
+}
+
+func NewParseTableIDFunction() function.Function {
+	return &parseTableIDFunction{}
+}
+
+func (f *parseTableIDFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_table_id"
+}
+
+func (f *parseTableIDFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parses the ID of a table or a view",
+		MarkdownDescription: "Splits the ID of a `clickhouse_table` or a `clickhouse_view`, `<cluster>:<database>:<name>` or `<database>:<name>`, into an object with the `cluster`, `database` and `name` attributes. `cluster` is empty when the ID doesn't contain it.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "Table or view ID",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"cluster":  types.StringType,
+				"database": types.StringType,
+				"name":     types.StringType,
+			},
+		},
+	}
+}
+
+func (f *parseTableIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+
+	var tableID tableIDModel
+	idParts := strings.Split(id, ":")
+	switch len(idParts) {
+	case 3:
+		tableID = tableIDModel{Cluster: idParts[0], Database: idParts[1], Name: idParts[2]}
+	case 2:
+		tableID = tableIDModel{Database: idParts[0], Name: idParts[1]}
+	default:
+		resp.Error = function.NewArgumentFuncError(0, "invalid table ID, expected <cluster>:<database>:<name> or <database>:<name>")
+		return
+	}
+	if tableID.Database == "" || tableID.Name == "" {
+		resp.Error = function.NewArgumentFuncError(0, "invalid table ID, the database and the name can't be empty")
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, tableID))
+}
@@ -0,0 +1,79 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &quoteStringFunction{}
+	_ function.Function = &quoteIdentifierFunction{}
+)
+
+type quoteStringFunction struct{}
+
+func NewQuoteStringFunction() function.Function {
+	return &quoteStringFunction{}
+}
+
+func (f *quoteStringFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "quote_string"
+}
+
+func (f *quoteStringFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Quotes a Clickhouse string literal",
+		MarkdownDescription: "Returns the value as a Clickhouse string literal, enclosed in single quotes with the quotes and backslashes escaped, e.g. to pass a value in `engine_params`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to quote",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *quoteStringFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, common.QuoteString(value)))
+}
+
+type quoteIdentifierFunction struct{}
+
+func NewQuoteIdentifierFunction() function.Function {
+	return &quoteIdentifierFunction{}
+}
+
+func (f *quoteIdentifierFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "quote_identifier"
+}
+
+func (f *quoteIdentifierFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Quotes a Clickhouse identifier",
+		MarkdownDescription: "Returns the name as a Clickhouse identifier, enclosed in backquotes with the backquotes and backslashes escaped, e.g. a database or column name containing dots or dashes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Identifier to quote",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *quoteIdentifierFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, common.QuoteIdentifier(name)))
+}
@@ -0,0 +1,66 @@
+package functions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &replicatedPathFunction{}
+
+type replicatedPathFunction struct{}
+
+func NewReplicatedPathFunction() function.Function {
+	return &replicatedPathFunction{}
+}
+
+func (f *replicatedPathFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "replicated_path"
+}
+
+func (f *replicatedPathFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Builds the Keeper path of a replicated table",
+		MarkdownDescription: "Returns the Keeper path of a replicated table, `<prefix>/tables/{shard}/<database>/<table>`, where `{shard}` is expanded by the server from its macros. Use it with `quote_string` as the first parameter of a `Replicated*MergeTree` engine.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "prefix",
+				MarkdownDescription: "Absolute path the tables are stored under, e.g. `/clickhouse` or `/clickhouse/{installation}/{cluster}`",
+			},
+			function.StringParameter{
+				Name:                "database",
+				MarkdownDescription: "Database name",
+			},
+			function.StringParameter{
+				Name:                "table",
+				MarkdownDescription: "Table name",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *replicatedPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var prefix, database, table string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &prefix, &database, &table))
+	if resp.Error != nil {
+		return
+	}
+
+	if !strings.HasPrefix(prefix, "/") {
+		resp.Error = function.NewArgumentFuncError(0, "prefix must be an absolute path, starting with /")
+		return
+	}
+	if database == "" || strings.Contains(database, "/") {
+		resp.Error = function.NewArgumentFuncError(1, "database must be a non empty name without /")
+		return
+	}
+	if table == "" || strings.Contains(table, "/") {
+		resp.Error = function.NewArgumentFuncError(2, "table must be a non empty name without /")
+		return
+	}
+
+	path := strings.TrimRight(prefix, "/") + "/tables/{shard}/" + database + "/" + table
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, path))
+}
@@ -1,5 +1,418 @@
 package models
 
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+)
+
 type CHDBResources struct {
 	CHTables []CHTable
 }
+
+// CHDatabase is a database read from `system.databases`, by clickhouse_db and the data sources
+type CHDatabase struct {
+	Name         string `json:"name" ch:"name"`
+	Engine       string `json:"engine" ch:"engine"`
+	EngineFull   string `json:"engine_full" ch:"engine_full"`
+	DataPath     string `json:"data_path" ch:"data_path"`
+	MetadataPath string `json:"metadata_path" ch:"metadata_path"`
+	UUID         string `json:"uuid" ch:"uuid"`
+	Comment      string `json:"comment" ch:"comment"`
+}
+
+// SystemDatabases are the databases of the server itself
+var SystemDatabases = []string{"system", "INFORMATION_SCHEMA", "information_schema"}
+
+// DatabaseFilter selects the databases read by the clickhouse_dbs data source, the empty fields
+// don't filter. Limit and Offset page through the databases ordered by name, a zero Limit reading
+// them all.
+type DatabaseFilter struct {
+	NameRegex       string
+	Engines         []string
+	ExcludeSystem   bool
+	CommentContains string
+	Limit           uint64
+	Offset          uint64
+}
+
+// DatabaseResource is the database configured by clickhouse_db
+type DatabaseResource struct {
+	Name    string
+	Cluster string
+	// Engine is nil when the database gets the default engine of the server
+	Engine     *DatabaseEngine
+	Comment    string
+	CreateMode string
+}
+
+// DatabaseEngine parses the engine of the database, `engine_full` being empty on some servers for
+// the engines without arguments
+func (d *CHDatabase) DatabaseEngine() (DatabaseEngine, error) {
+	if d.EngineFull == "" {
+		return ParseDatabaseEngine(d.Engine)
+	}
+	return ParseDatabaseEngine(d.EngineFull)
+}
+
+func (d *CHDatabase) ToResource() (*DatabaseResource, error) {
+	engine, err := d.DatabaseEngine()
+	if err != nil {
+		return nil, err
+	}
+	return &DatabaseResource{
+		Name:    d.Name,
+		Engine:  &engine,
+		Comment: d.Comment,
+	}, nil
+}
+
+// Differences lists what differs between the database and an existing one, in order to adopt it.
+// The engine is compared with its arguments and settings but the password, which the server hides.
+func (d *DatabaseResource) Differences(existing *DatabaseResource) []string {
+	var differences []string
+	if d.Engine != nil && existing.Engine != nil {
+		configured, existingEngine := *d.Engine, *existing.Engine
+		configured.Password, existingEngine.Password = "", ""
+		// The arguments of a Replicated engine configured without any default to the server ones
+		if configured.Name == DatabaseEngineReplicated && configured.ZookeeperPath == "" {
+			existingEngine.ZookeeperPath, existingEngine.ShardName, existingEngine.ReplicaName = "", "", ""
+		}
+		if len(configured.Settings) == 0 {
+			configured.Settings = nil
+		}
+		if len(existingEngine.Settings) == 0 {
+			existingEngine.Settings = nil
+		}
+		if !reflect.DeepEqual(configured, existingEngine) {
+			differences = append(differences, fmt.Sprintf("engine is %s instead of %s", existingEngine.SQL(), configured.SQL()))
+		}
+	}
+	if d.Comment != existing.Comment {
+		differences = append(differences, fmt.Sprintf("comment is %q instead of %q", existing.Comment, d.Comment))
+	}
+	return differences
+}
+
+// Kinds of the objects contained in a database, dropped in this order by `force_destroy`
+const (
+	DatabaseObjectDictionary       = "dictionary"
+	DatabaseObjectMaterializedView = "materialized view"
+	DatabaseObjectView             = "view"
+	DatabaseObjectTable            = "table"
+)
+
+// CHDatabaseObject is a table, view or dictionary of a database, read from `system.tables`. The
+// dependencies are the objects reading from it, e.g. the materialized views selecting from a table.
+type CHDatabaseObject struct {
+	Database             string   `ch:"database"`
+	Name                 string   `ch:"name"`
+	Engine               string   `ch:"engine"`
+	IsDictionary         bool     `ch:"is_dictionary"`
+	DependenciesDatabase []string `ch:"dependencies_database"`
+	DependenciesTable    []string `ch:"dependencies_table"`
+}
+
+// Kind returns the kind of the object, one of the DatabaseObject constants
+func (o CHDatabaseObject) Kind() string {
+	switch {
+	case o.IsDictionary:
+		return DatabaseObjectDictionary
+	case o.Engine == "MaterializedView":
+		return DatabaseObjectMaterializedView
+	case o.Engine == "View" || o.Engine == "LiveView" || o.Engine == "WindowView":
+		return DatabaseObjectView
+	default:
+		return DatabaseObjectTable
+	}
+}
+
+// String describes the object, e.g. `materialized view analytics.events_mv`
+func (o CHDatabaseObject) String() string {
+	return fmt.Sprintf("%s %s.%s", o.Kind(), o.Database, o.Name)
+}
+
+// Database engines which can be configured on clickhouse_db
+const (
+	DatabaseEngineAtomic     = "Atomic"
+	DatabaseEngineReplicated = "Replicated"
+	DatabaseEngineLazy       = "Lazy"
+	DatabaseEngineMemory     = "Memory"
+	DatabaseEngineMySQL      = "MySQL"
+	DatabaseEnginePostgreSQL = "PostgreSQL"
+	DatabaseEngineSQLite     = "SQLite"
+)
+
+var DatabaseEngines = []string{
+	DatabaseEngineAtomic,
+	DatabaseEngineReplicated,
+	DatabaseEngineLazy,
+	DatabaseEngineMemory,
+	DatabaseEngineMySQL,
+	DatabaseEnginePostgreSQL,
+	DatabaseEngineSQLite,
+}
+
+// DatabaseEngine is the engine of a database with its arguments, as written in the ENGINE clause
+// of CREATE DATABASE and read back from `system.databases.engine_full`
+type DatabaseEngine struct {
+	Name string
+	// Replicated
+	ZookeeperPath string
+	ShardName     string
+	ReplicaName   string
+	// Lazy
+	ExpirationTimeInSeconds int64
+	// MySQL and PostgreSQL, Password is never read back since the server hides it
+	Host     string
+	Database string
+	User     string
+	Password string
+	Schema   string
+	// SQLite
+	Path     string
+	Settings map[string]string
+}
+
+// databaseEngineArguments lists the arguments of the engines accepting some, in order
+var databaseEngineArguments = map[string][]string{
+	DatabaseEngineReplicated: {"zookeeper_path", "shard_name", "replica_name"},
+	DatabaseEngineLazy:       {"expiration_time_in_seconds"},
+	DatabaseEngineMySQL:      {"host", "database", "user", "password"},
+	DatabaseEnginePostgreSQL: {"host", "database", "user", "password", "schema"},
+	DatabaseEngineSQLite:     {"path"},
+}
+
+// arguments returns the arguments of the engine by name, the unset ones being empty
+func (e DatabaseEngine) arguments() map[string]string {
+	expiration := ""
+	if e.ExpirationTimeInSeconds != 0 {
+		expiration = strconv.FormatInt(e.ExpirationTimeInSeconds, 10)
+	}
+	return map[string]string{
+		"zookeeper_path":             e.ZookeeperPath,
+		"shard_name":                 e.ShardName,
+		"replica_name":               e.ReplicaName,
+		"expiration_time_in_seconds": expiration,
+		"host":                       e.Host,
+		"database":                   e.Database,
+		"user":                       e.User,
+		"password":                   e.Password,
+		"schema":                     e.Schema,
+		"path":                       e.Path,
+	}
+}
+
+// Validate checks that the engine is given the arguments it requires and only those
+func (e DatabaseEngine) Validate() error {
+	accepted := map[string]bool{}
+	for _, name := range databaseEngineArguments[e.Name] {
+		accepted[name] = true
+	}
+	arguments := e.arguments()
+	var names []string
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if arguments[name] != "" && !accepted[name] {
+			return fmt.Errorf("%s is not an argument of the %s engine", name, e.Name)
+		}
+	}
+
+	var required []string
+	switch e.Name {
+	case DatabaseEngineReplicated:
+		// The arguments default to the server configuration when none is given
+		if e.ZookeeperPath != "" || e.ShardName != "" || e.ReplicaName != "" {
+			required = []string{"zookeeper_path", "shard_name", "replica_name"}
+		}
+	case DatabaseEngineLazy:
+		required = []string{"expiration_time_in_seconds"}
+	case DatabaseEngineMySQL, DatabaseEnginePostgreSQL:
+		required = []string{"host", "database", "user"}
+	case DatabaseEngineSQLite:
+		required = []string{"path"}
+	}
+	for _, name := range required {
+		if arguments[name] == "" {
+			return fmt.Errorf("the %s engine requires %s", e.Name, name)
+		}
+	}
+	return nil
+}
+
+// SQL returns the engine as written after `ENGINE =`, e.g. `Lazy(3600)` or
+// `Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')`
+func (e DatabaseEngine) SQL() string {
+	var arguments []string
+	switch e.Name {
+	case DatabaseEngineReplicated:
+		if e.ZookeeperPath != "" {
+			arguments = []string{common.QuoteString(e.ZookeeperPath), common.QuoteString(e.ShardName), common.QuoteString(e.ReplicaName)}
+		}
+	case DatabaseEngineLazy:
+		arguments = []string{strconv.FormatInt(e.ExpirationTimeInSeconds, 10)}
+	case DatabaseEngineMySQL, DatabaseEnginePostgreSQL:
+		arguments = []string{common.QuoteString(e.Host), common.QuoteString(e.Database), common.QuoteString(e.User), common.QuoteString(e.Password)}
+		if e.Schema != "" {
+			arguments = append(arguments, common.QuoteString(e.Schema))
+		}
+	case DatabaseEngineSQLite:
+		arguments = []string{common.QuoteString(e.Path)}
+	}
+
+	sql := e.Name
+	if len(arguments) > 0 {
+		sql += "(" + strings.Join(arguments, ", ") + ")"
+	}
+	if len(e.Settings) > 0 {
+		var settings []string
+		for key, value := range e.Settings {
+			settings = append(settings, fmt.Sprintf("%s = %s", key, common.QuoteString(value)))
+		}
+		sort.Strings(settings)
+		sql += " SETTINGS " + strings.Join(settings, ", ")
+	}
+	return sql
+}
+
+var engineNameRegexp = regexp.MustCompile(`^\w+`)
+
+// ParseDatabaseEngine reads the engine of a database from `system.databases.engine_full`
+func ParseDatabaseEngine(engineFull string) (DatabaseEngine, error) {
+	engineFull = strings.TrimSpace(engineFull)
+	engine := DatabaseEngine{Name: engineNameRegexp.FindString(engineFull)}
+	if engine.Name == "" {
+		return engine, fmt.Errorf("invalid database engine %q", engineFull)
+	}
+	rest := strings.TrimSpace(engineFull[len(engine.Name):])
+
+	var arguments []string
+	if strings.HasPrefix(rest, "(") {
+		end := closingParenthesis(rest)
+		if end < 0 {
+			return engine, fmt.Errorf("invalid database engine %q: unbalanced parentheses", engineFull)
+		}
+		arguments = splitArguments(rest[1:end])
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+
+	if strings.HasPrefix(strings.ToUpper(rest), "SETTINGS") {
+		engine.Settings = map[string]string{}
+		for _, setting := range splitArguments(rest[len("SETTINGS"):]) {
+			key, value, ok := strings.Cut(setting, "=")
+			if !ok {
+				return engine, fmt.Errorf("invalid database engine %q: setting %q", engineFull, setting)
+			}
+			engine.Settings[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		}
+	}
+
+	for i, argument := range arguments {
+		names := databaseEngineArguments[engine.Name]
+		if i >= len(names) {
+			break
+		}
+		value := unquote(argument)
+		switch names[i] {
+		case "zookeeper_path":
+			engine.ZookeeperPath = value
+		case "shard_name":
+			engine.ShardName = value
+		case "replica_name":
+			engine.ReplicaName = value
+		case "expiration_time_in_seconds":
+			expiration, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return engine, fmt.Errorf("invalid database engine %q: %v", engineFull, err)
+			}
+			engine.ExpirationTimeInSeconds = expiration
+		case "host":
+			engine.Host = value
+		case "database":
+			engine.Database = value
+		case "user":
+			engine.User = value
+		case "schema":
+			engine.Schema = value
+		case "path":
+			engine.Path = value
+		}
+	}
+	return engine, nil
+}
+
+// closingParenthesis returns the index of the parenthesis closing the one starting the text,
+// ignoring the ones in string literals, or -1
+func closingParenthesis(text string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitArguments splits a list of arguments on the commas which are not part of a string literal
+// or of a nested call
+func splitArguments(text string) []string {
+	var arguments []string
+	depth := 0
+	inString := false
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			arguments = append(arguments, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" || len(arguments) > 0 {
+		arguments = append(arguments, last)
+	}
+	return arguments
+}
+
+// unquote returns the value of a string literal, other values are returned as is
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+	var unquoted strings.Builder
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && i+1 < len(value)-1 {
+			i++
+		}
+		unquoted.WriteByte(value[i])
+	}
+	return unquoted.String()
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDatabaseEngineSQL(t *testing.T) {
+	testCases := []struct {
+		engine      DatabaseEngine
+		expectedSQL string
+	}{
+		{
+			engine:      DatabaseEngine{Name: DatabaseEngineAtomic},
+			expectedSQL: "Atomic",
+		},
+		{
+			engine:      DatabaseEngine{Name: DatabaseEngineReplicated},
+			expectedSQL: "Replicated",
+		},
+		{
+			engine: DatabaseEngine{
+				Name:          DatabaseEngineReplicated,
+				ZookeeperPath: "/clickhouse/databases/analytics",
+				ShardName:     "{shard}",
+				ReplicaName:   "{replica}",
+				Settings:      map[string]string{"max_broken_tables_ratio": "1", "collection_name": "it's"},
+			},
+			expectedSQL: `Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') SETTINGS collection_name = 'it\'s', max_broken_tables_ratio = '1'`,
+		},
+		{
+			engine:      DatabaseEngine{Name: DatabaseEngineLazy, ExpirationTimeInSeconds: 3600},
+			expectedSQL: "Lazy(3600)",
+		},
+		{
+			engine:      DatabaseEngine{Name: DatabaseEngineMySQL, Host: "mysql:3306", Database: "shop", User: "reader", Password: "secret"},
+			expectedSQL: "MySQL('mysql:3306', 'shop', 'reader', 'secret')",
+		},
+		{
+			engine:      DatabaseEngine{Name: DatabaseEnginePostgreSQL, Host: "pg:5432", Database: "shop", User: "reader", Password: "secret", Schema: "public"},
+			expectedSQL: "PostgreSQL('pg:5432', 'shop', 'reader', 'secret', 'public')",
+		},
+		{
+			engine:      DatabaseEngine{Name: DatabaseEngineSQLite, Path: "/data/shop.db"},
+			expectedSQL: "SQLite('/data/shop.db')",
+		},
+	}
+
+	for _, tt := range testCases {
+		if sql := tt.engine.SQL(); sql != tt.expectedSQL {
+			t.Errorf("SQL() = %q, expected %q", sql, tt.expectedSQL)
+		}
+	}
+}
+
+func TestDatabaseEngineValidate(t *testing.T) {
+	testCases := []struct {
+		engine        DatabaseEngine
+		expectedError string
+	}{
+		{engine: DatabaseEngine{Name: DatabaseEngineAtomic}},
+		{engine: DatabaseEngine{Name: DatabaseEngineReplicated}},
+		{
+			engine:        DatabaseEngine{Name: DatabaseEngineReplicated, ZookeeperPath: "/clickhouse/databases/analytics"},
+			expectedError: "the Replicated engine requires shard_name",
+		},
+		{
+			engine:        DatabaseEngine{Name: DatabaseEngineAtomic, Path: "/data/shop.db"},
+			expectedError: "path is not an argument of the Atomic engine",
+		},
+		{
+			engine:        DatabaseEngine{Name: DatabaseEngineLazy},
+			expectedError: "the Lazy engine requires expiration_time_in_seconds",
+		},
+		{
+			engine:        DatabaseEngine{Name: DatabaseEngineMySQL, Host: "mysql:3306", Database: "shop", User: "reader", Schema: "public"},
+			expectedError: "schema is not an argument of the MySQL engine",
+		},
+		{engine: DatabaseEngine{Name: DatabaseEnginePostgreSQL, Host: "pg:5432", Database: "shop", User: "reader", Schema: "public"}},
+	}
+
+	for _, tt := range testCases {
+		err := tt.engine.Validate()
+		switch {
+		case tt.expectedError == "" && err != nil:
+			t.Errorf("Validate(%s) error = %v", tt.engine.Name, err)
+		case tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError):
+			t.Errorf("Validate(%s) error = %v, expected %q", tt.engine.Name, err, tt.expectedError)
+		}
+	}
+}
+
+func TestParseDatabaseEngine(t *testing.T) {
+	testCases := []struct {
+		engineFull     string
+		expectedEngine DatabaseEngine
+	}{
+		{
+			engineFull:     "Atomic",
+			expectedEngine: DatabaseEngine{Name: DatabaseEngineAtomic},
+		},
+		{
+			engineFull: `Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 1, collection_name = 'it\'s'`,
+			expectedEngine: DatabaseEngine{
+				Name:          DatabaseEngineReplicated,
+				ZookeeperPath: "/clickhouse/databases/analytics",
+				ShardName:     "{shard}",
+				ReplicaName:   "{replica}",
+				Settings:      map[string]string{"max_broken_tables_ratio": "1", "collection_name": "it's"},
+			},
+		},
+		{
+			engineFull:     "Lazy(3600)",
+			expectedEngine: DatabaseEngine{Name: DatabaseEngineLazy, ExpirationTimeInSeconds: 3600},
+		},
+		{
+			// The password is hidden by the server
+			engineFull:     "PostgreSQL('pg:5432', 'shop', 'reader', '[HIDDEN]', 'public')",
+			expectedEngine: DatabaseEngine{Name: DatabaseEnginePostgreSQL, Host: "pg:5432", Database: "shop", User: "reader", Schema: "public"},
+		},
+		{
+			engineFull:     "SQLite('/data/shop, (copy).db')",
+			expectedEngine: DatabaseEngine{Name: DatabaseEngineSQLite, Path: "/data/shop, (copy).db"},
+		},
+	}
+
+	for _, tt := range testCases {
+		engine, err := ParseDatabaseEngine(tt.engineFull)
+		if err != nil {
+			t.Errorf("ParseDatabaseEngine(%q) error = %v", tt.engineFull, err)
+			continue
+		}
+		if !reflect.DeepEqual(engine, tt.expectedEngine) {
+			t.Errorf("ParseDatabaseEngine(%q) = %+v, expected %+v", tt.engineFull, engine, tt.expectedEngine)
+		}
+	}
+
+	for _, engineFull := range []string{"", "Replicated('/clickhouse", "Lazy(soon)"} {
+		if _, err := ParseDatabaseEngine(engineFull); err == nil {
+			t.Errorf("ParseDatabaseEngine(%q) expected an error", engineFull)
+		}
+	}
+}
+
+func TestDatabaseResourceDifferences(t *testing.T) {
+	replicated := func(zookeeperPath string) *DatabaseEngine {
+		return &DatabaseEngine{Name: DatabaseEngineReplicated, ZookeeperPath: zookeeperPath, ShardName: "{shard}", ReplicaName: "{replica}"}
+	}
+
+	testCases := []struct {
+		name                string
+		engine              *DatabaseEngine
+		existingEngine      *DatabaseEngine
+		expectedDifferences []string
+	}{
+		{name: "server default engine", existingEngine: &DatabaseEngine{Name: DatabaseEngineAtomic}},
+		{name: "identical", engine: replicated("/clickhouse/databases/analytics"), existingEngine: replicated("/clickhouse/databases/analytics")},
+		{
+			name:           "replicated arguments of the server",
+			engine:         &DatabaseEngine{Name: DatabaseEngineReplicated},
+			existingEngine: replicated("/clickhouse/databases/{uuid}"),
+		},
+		{
+			name:           "zookeeper path",
+			engine:         replicated("/clickhouse/databases/analytics"),
+			existingEngine: replicated("/clickhouse/databases/other"),
+			expectedDifferences: []string{
+				"engine is Replicated('/clickhouse/databases/other', '{shard}', '{replica}') instead of Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')",
+			},
+		},
+		{
+			name:                "settings",
+			engine:              &DatabaseEngine{Name: DatabaseEngineAtomic, Settings: map[string]string{"max_broken_tables_ratio": "1"}},
+			existingEngine:      &DatabaseEngine{Name: DatabaseEngineAtomic},
+			expectedDifferences: []string{"engine is Atomic instead of Atomic SETTINGS max_broken_tables_ratio = '1'"},
+		},
+		{
+			// The server hides the password
+			name:           "password",
+			engine:         &DatabaseEngine{Name: DatabaseEngineMySQL, Host: "mysql:3306", Database: "shop", User: "reader", Password: "secret"},
+			existingEngine: &DatabaseEngine{Name: DatabaseEngineMySQL, Host: "mysql:3306", Database: "shop", User: "reader"},
+		},
+		{
+			name:                "host",
+			engine:              &DatabaseEngine{Name: DatabaseEngineMySQL, Host: "mysql:3306", Database: "shop", User: "reader"},
+			existingEngine:      &DatabaseEngine{Name: DatabaseEngineMySQL, Host: "replica:3306", Database: "shop", User: "reader"},
+			expectedDifferences: []string{"engine is MySQL('replica:3306', 'shop', 'reader', '') instead of MySQL('mysql:3306', 'shop', 'reader', '')"},
+		},
+	}
+
+	for _, tt := range testCases {
+		database := DatabaseResource{Name: "analytics", Engine: tt.engine}
+		differences := database.Differences(&DatabaseResource{Name: "analytics", Engine: tt.existingEngine})
+		if !reflect.DeepEqual(differences, tt.expectedDifferences) {
+			t.Errorf("%s: Differences() = %q, expected %q", tt.name, differences, tt.expectedDifferences)
+		}
+	}
+}
@@ -20,6 +20,7 @@ type CHRole struct {
 
 type RoleResource struct {
 	Name       string
+	Cluster    string
 	Database   string
 	Privileges *schema.Set
 }
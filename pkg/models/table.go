@@ -3,20 +3,24 @@ package models
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
 type CHTable struct {
-	Database   string     `ch:"database"`
-	Name       string     `ch:"name"`
-	EngineFull string     `ch:"engine_full"`
-	SortingKey string     `ch:"sorting_key"`
-	Engine     string     `ch:"engine"`
-	Comment    string     `ch:"comment"`
-	Columns    []CHColumn `ch:"columns"`
-	Indexes    []CHIndex  `ch:"indexes"`
+	Database   string `ch:"database"`
+	Name       string `ch:"name"`
+	EngineFull string `ch:"engine_full"`
+	SortingKey string `ch:"sorting_key"`
+	// PartitionKey and PrimaryKey are only compared to the configuration by adopt
+	PartitionKey string     `ch:"partition_key"`
+	PrimaryKey   string     `ch:"primary_key"`
+	Engine       string     `ch:"engine"`
+	Comment      string     `ch:"comment"`
+	Columns      []CHColumn `ch:"columns"`
+	Indexes      []CHIndex  `ch:"indexes"`
 }
 
 type CHIndex struct {
@@ -37,6 +41,15 @@ type CHColumn struct {
 	CompressionCodec  string `ch:"compression_codec"`
 }
 
+// CHMutation is a mutation of a table, read from `system.mutations` of a host
+type CHMutation struct {
+	Host             string `ch:"host"`
+	MutationID       string `ch:"mutation_id"`
+	Command          string `ch:"command"`
+	IsDone           bool   `ch:"is_done"`
+	LatestFailReason string `ch:"latest_fail_reason"`
+}
+
 type TableResource struct {
 	Database     string
 	Name         string
@@ -52,6 +65,7 @@ type TableResource struct {
 	Indexes      []IndexDefinition
 	Settings     map[string]string
 	TTL          map[string]string
+	CreateMode   string
 }
 
 type IndexDefinition struct {
@@ -76,6 +90,17 @@ type PartitionByResource struct {
 	Mod               string
 }
 
+// Expression returns the partition expression, e.g. `toYYYYMM(event_date) % 4`
+func (p PartitionByResource) Expression() string {
+	if p.PartitionFunction == "" {
+		return p.By
+	}
+	if p.Mod == "" {
+		return fmt.Sprintf("%v(%v)", p.PartitionFunction, p.By)
+	}
+	return fmt.Sprintf("%v(%v) %% %v", p.PartitionFunction, p.By, p.Mod)
+}
+
 func (t *CHTable) IndexesToResource() []IndexDefinition {
 	indexResources := make([]IndexDefinition, len(t.Indexes))
 	for i, index := range t.Indexes {
@@ -116,18 +141,39 @@ func (t *CHTable) ToResource() (*TableResource, error) {
 	return &tableResource, nil
 }
 
+// GetEngineParams returns the parameters of the engine, the nested calls like `rand()` and the
+// string literals being kept whole
 func GetEngineParams(engineFull string) []string {
-	r := regexp.MustCompile(`^\w+\(([^)]*)\)`)
-	match := r.FindStringSubmatch(engineFull)
-	var engineParams []string
-	if len(match) > 1 {
-		values := strings.Split(match[1], ",")
-		for _, value := range values {
-			value = strings.TrimSpace(value)
-			engineParams = append(engineParams, strings.TrimSpace(value))
+	name := regexp.MustCompile(`^\w+`).FindString(engineFull)
+	rest := engineFull[len(name):]
+	if name == "" || !strings.HasPrefix(rest, "(") {
+		return nil
+	}
+	end := closingParenthesis(rest)
+	if end < 0 {
+		return nil
+	}
+	return splitArguments(rest[1:end])
+}
+
+// engineFullClauses are the clauses following the engine in `system.tables.engine_full`, in order
+var engineFullClauses = []string{"PARTITION BY", "PRIMARY KEY", "ORDER BY", "SAMPLE BY", "TTL", "SETTINGS"}
+
+// engineFullClause returns the expression of a clause of `engine_full`, e.g. the TTL rules, or
+// an empty string when the table doesn't have it
+func engineFullClause(engineFull string, clause string) string {
+	start := strings.Index(engineFull, " "+clause+" ")
+	if start < 0 {
+		return ""
+	}
+	rest := engineFull[start+len(clause)+2:]
+	end := len(rest)
+	for _, next := range engineFullClauses {
+		if index := strings.Index(rest, " "+next+" "); index >= 0 && index < end {
+			end = index
 		}
 	}
-	return engineParams
+	return strings.TrimSpace(rest[:end])
 }
 
 func GetOrderBy(sortingKey string) []string {
@@ -247,3 +293,147 @@ func (t *TableResource) SetIndexes(indexes []interface{}) {
 		t.Indexes = append(t.Indexes, indexDefinition)
 	}
 }
+
+// defaultTableSettings are the settings the server adds to every MergeTree table
+var defaultTableSettings = map[string]string{"index_granularity": "8192"}
+
+// Differences lists what differs between the table and an existing one, in order to adopt it. The
+// expressions are compared as the server rewrites them, e.g. `INTERVAL 1 MONTH` differs from the
+// `toIntervalMonth(1)` of an existing TTL.
+func (t *TableResource) Differences(existing *CHTable) []string {
+	var differences []string
+	differ := func(description string, existing []string, configured []string) {
+		if !sameExpressions(existing, configured) {
+			differences = append(differences, fmt.Sprintf("%s (%s) instead of (%s)", description, strings.Join(existing, ", "), strings.Join(configured, ", ")))
+		}
+	}
+
+	if t.Engine != existing.Engine {
+		differences = append(differences, fmt.Sprintf("engine is %s instead of %s", existing.Engine, t.Engine))
+	}
+	differ("engine params are", unquoteAll(removeDefaultParams(GetEngineParams(existing.EngineFull))), unquoteAll(removeDefaultParams(t.EngineParams)))
+	differ("order by is", GetOrderBy(existing.SortingKey), t.OrderBy)
+
+	// The primary key defaults to the sorting key
+	primaryKey := t.PrimaryKey
+	if len(primaryKey) == 0 {
+		primaryKey = t.OrderBy
+	}
+	differ("primary key is", splitArguments(existing.PrimaryKey), primaryKey)
+
+	var partitionBy []string
+	for _, partition := range t.PartitionBy {
+		partitionBy = append(partitionBy, partition.Expression())
+	}
+	differ("partition by is", splitArguments(existing.PartitionKey), partitionBy)
+
+	var ttl []string
+	for expression, action := range t.TTL {
+		ttl = append(ttl, strings.TrimSpace(expression+" "+action))
+	}
+	sort.Strings(ttl)
+	existingTTL := splitArguments(engineFullClause(existing.EngineFull, "TTL"))
+	sort.Strings(existingTTL)
+	if !sameExpressions(trimDeleteActions(existingTTL), trimDeleteActions(ttl)) {
+		differences = append(differences, fmt.Sprintf("ttl is (%s) instead of (%s)", strings.Join(existingTTL, ", "), strings.Join(ttl, ", ")))
+	}
+
+	differences = append(differences, t.settingsDifferences(existing)...)
+
+	if t.Comment != existing.Comment {
+		differences = append(differences, fmt.Sprintf("comment is %q instead of %q", existing.Comment, t.Comment))
+	}
+
+	existingColumns := map[string]string{}
+	for _, column := range existing.Columns {
+		existingColumns[column.Name] = column.Type
+	}
+	for _, column := range t.Columns {
+		existingType, ok := existingColumns[column.Name]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("column %s is missing", column.Name))
+		} else if existingType != column.Type {
+			differences = append(differences, fmt.Sprintf("column %s is %s instead of %s", column.Name, existingType, column.Type))
+		}
+		delete(existingColumns, column.Name)
+	}
+	for _, column := range existing.Columns {
+		if _, ok := existingColumns[column.Name]; ok {
+			differences = append(differences, fmt.Sprintf("column %s is not configured", column.Name))
+		}
+	}
+	return differences
+}
+
+// settingsDifferences compares the settings with the ones of the existing table, ignoring the
+// settings the server adds by default
+func (t *TableResource) settingsDifferences(existing *CHTable) []string {
+	existingSettings := map[string]string{}
+	for _, setting := range splitArguments(engineFullClause(existing.EngineFull, "SETTINGS")) {
+		if key, value, ok := strings.Cut(setting, "="); ok {
+			existingSettings[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		}
+	}
+
+	var differences []string
+	var names []string
+	for name := range t.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := existingSettings[name]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("setting %s is not set", name))
+		case value != t.Settings[name]:
+			differences = append(differences, fmt.Sprintf("setting %s is %q instead of %q", name, value, t.Settings[name]))
+		}
+		delete(existingSettings, name)
+	}
+
+	names = nil
+	for name, value := range existingSettings {
+		if defaultTableSettings[name] != value {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		differences = append(differences, fmt.Sprintf("setting %s is not configured", name))
+	}
+	return differences
+}
+
+// sameExpressions compares two lists of expressions regardless of their whitespaces, which the
+// server rewrites
+func sameExpressions(expressions []string, others []string) bool {
+	if len(expressions) != len(others) {
+		return false
+	}
+	for i := range expressions {
+		if strings.Join(strings.Fields(expressions[i]), "") != strings.Join(strings.Fields(others[i]), "") {
+			return false
+		}
+	}
+	return true
+}
+
+// unquoteAll returns the values of the string literals, the server quoting some engine params
+// given as identifiers, e.g. the cluster of a Distributed table
+func unquoteAll(values []string) []string {
+	unquoted := make([]string, len(values))
+	for i, value := range values {
+		unquoted[i] = unquote(strings.TrimSpace(value))
+	}
+	return unquoted
+}
+
+// trimDeleteActions removes the DELETE actions of the TTL rules, DELETE being the default action
+func trimDeleteActions(rules []string) []string {
+	trimmed := make([]string, len(rules))
+	for i, rule := range rules {
+		trimmed[i] = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rule), "DELETE"))
+	}
+	return trimmed
+}
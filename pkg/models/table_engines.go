@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TableEngine describes what a table engine accepts in CREATE TABLE
+type TableEngine struct {
+	Name string
+	// MinParams and MaxParams bound the number of `engine_params`, MaxParams is -1 when unbounded
+	MinParams int
+	MaxParams int
+	// Replicated engines also accept the ZooKeeper path and the replica name before the parameters
+	// of their family, both defaulting to the server configuration
+	Replicated bool
+	// SortingKeyRequired engines need ORDER BY or PRIMARY KEY, e.g. `ORDER BY tuple()`
+	SortingKeyRequired bool
+	OrderBy            bool
+	PartitionBy        bool
+	PrimaryKey         bool
+	TTL                bool
+	Settings           bool
+}
+
+// mergeTreeFamily lists the MergeTree engines with their parameters, each one has a Replicated
+// variant
+var mergeTreeFamily = []TableEngine{
+	{Name: "MergeTree"},
+	// ReplacingMergeTree([ver [, is_deleted]])
+	{Name: "ReplacingMergeTree", MaxParams: 2},
+	// SummingMergeTree([columns])
+	{Name: "SummingMergeTree", MaxParams: 1},
+	{Name: "AggregatingMergeTree"},
+	// CollapsingMergeTree(sign)
+	{Name: "CollapsingMergeTree", MinParams: 1, MaxParams: 1},
+	// VersionedCollapsingMergeTree(sign, version)
+	{Name: "VersionedCollapsingMergeTree", MinParams: 2, MaxParams: 2},
+	// GraphiteMergeTree(config_section)
+	{Name: "GraphiteMergeTree", MinParams: 1, MaxParams: 1},
+}
+
+// TableEngines is the registry of the engines clickhouse_table accepts, by name
+var TableEngines = buildTableEngines()
+
+func buildTableEngines() map[string]TableEngine {
+	engines := map[string]TableEngine{}
+	for _, engine := range mergeTreeFamily {
+		engine.OrderBy = true
+		engine.PartitionBy = true
+		engine.PrimaryKey = true
+		engine.TTL = true
+		engine.Settings = true
+		engine.SortingKeyRequired = true
+		engines[engine.Name] = engine
+
+		replicated := engine
+		replicated.Name = "Replicated" + engine.Name
+		replicated.Replicated = true
+		engines[replicated.Name] = replicated
+	}
+
+	for _, engine := range []TableEngine{
+		// Distributed(cluster, database, table [, sharding_key [, policy_name]])
+		{Name: "Distributed", MinParams: 3, MaxParams: 5, Settings: true},
+		// Kafka(broker_list, topic_list, group_name, format, ...) or Kafka() SETTINGS ...
+		{Name: "Kafka", MaxParams: -1, Settings: true},
+		// Buffer(database, table, num_layers, min_time, max_time, min_rows, max_rows, min_bytes,
+		// max_bytes [, flush_time [, flush_rows [, flush_bytes]]])
+		{Name: "Buffer", MinParams: 9, MaxParams: 12},
+		{Name: "Memory", Settings: true},
+		{Name: "Log", Settings: true},
+		{Name: "TinyLog", Settings: true},
+		{Name: "StripeLog", Settings: true},
+		{Name: "Null"},
+		{Name: "Set", Settings: true},
+		// Join(join_strictness, join_type, k1 [, k2, ...])
+		{Name: "Join", MinParams: 3, MaxParams: -1, Settings: true},
+		// File(format [, compression])
+		{Name: "File", MinParams: 1, MaxParams: 2, PartitionBy: true, Settings: true},
+		// URL(url [, format [, compression]])
+		{Name: "URL", MinParams: 1, MaxParams: 3, PartitionBy: true, Settings: true},
+		// S3(path [, NOSIGN | aws_access_key_id, aws_secret_access_key] [, format [, compression]])
+		{Name: "S3", MinParams: 1, MaxParams: 6, PartitionBy: true, Settings: true},
+		// Merge(database, tables_regexp)
+		{Name: "Merge", MinParams: 2, MaxParams: 2},
+	} {
+		engines[engine.Name] = engine
+	}
+	return engines
+}
+
+// TableEngineNames returns the sorted names of the engines of the registry
+func TableEngineNames() []string {
+	var names []string
+	for name := range TableEngines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// acceptsParams tells whether the engine accepts that many parameters
+func (e TableEngine) acceptsParams(count int) bool {
+	inRange := func(count int) bool {
+		return count >= e.MinParams && (e.MaxParams < 0 || count <= e.MaxParams)
+	}
+	return inRange(count) || (e.Replicated && count >= 2 && inRange(count-2))
+}
+
+// paramsDescription describes the number of parameters the engine accepts, e.g. `1 to 3 parameters`
+func (e TableEngine) paramsDescription() string {
+	var description string
+	switch {
+	case e.MaxParams < 0:
+		description = fmt.Sprintf("at least %d", e.MinParams)
+	case e.MinParams == e.MaxParams:
+		description = fmt.Sprintf("%d", e.MinParams)
+	default:
+		description = fmt.Sprintf("%d to %d", e.MinParams, e.MaxParams)
+	}
+	description += " parameters"
+	if e.Replicated {
+		description += ", after the optional ZooKeeper path and replica name"
+	}
+	return description
+}
+
+// ValidateEngine checks the table against what its engine accepts: the number of `engine_params`
+// and the ORDER BY, PARTITION BY, PRIMARY KEY, TTL and SETTINGS clauses. The engines missing from
+// the registry aren't checked.
+func (t *TableResource) ValidateEngine() error {
+	engine, ok := TableEngines[t.Engine]
+	if !ok {
+		return nil
+	}
+	if !engine.acceptsParams(len(t.EngineParams)) {
+		return fmt.Errorf("the %s engine takes %s, got %d", engine.Name, engine.paramsDescription(), len(t.EngineParams))
+	}
+
+	clauses := []struct {
+		name      string
+		set       bool
+		supported bool
+	}{
+		{name: "order_by", set: len(t.OrderBy) > 0, supported: engine.OrderBy},
+		{name: "partition_by", set: len(t.PartitionBy) > 0, supported: engine.PartitionBy},
+		{name: "primary_key", set: len(t.PrimaryKey) > 0, supported: engine.PrimaryKey},
+		{name: "ttl", set: len(t.TTL) > 0, supported: engine.TTL},
+		{name: "settings", set: len(t.Settings) > 0, supported: engine.Settings},
+	}
+	for _, clause := range clauses {
+		if clause.set && !clause.supported {
+			return fmt.Errorf("the %s engine doesn't support %s", engine.Name, clause.name)
+		}
+	}
+	if engine.SortingKeyRequired && len(t.OrderBy) == 0 && len(t.PrimaryKey) == 0 {
+		return fmt.Errorf("the %s engine requires order_by or primary_key, e.g. order_by = [\"tuple()\"] for no sorting key", engine.Name)
+	}
+	return nil
+}
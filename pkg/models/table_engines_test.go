@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func TestTableResourceValidateEngine(t *testing.T) {
+	testCases := []struct {
+		table         TableResource
+		expectedError string
+	}{
+		{table: TableResource{Engine: "ReplicatedReplacingMergeTree", OrderBy: []string{"id"}, TTL: map[string]string{"created_at + INTERVAL 1 DAY": "DELETE"}}},
+		{table: TableResource{Engine: "ReplicatedReplacingMergeTree", EngineParams: []string{"'/clickhouse/tables/{shard}/events'", "'{replica}'"}, OrderBy: []string{"id"}}},
+		{table: TableResource{Engine: "ReplicatedReplacingMergeTree", EngineParams: []string{"'/clickhouse/tables/{shard}/events'", "'{replica}'", "version"}, OrderBy: []string{"id"}}},
+		{
+			table:         TableResource{Engine: "ReplicatedCollapsingMergeTree", EngineParams: []string{"'/clickhouse/tables/{shard}/events'", "'{replica}'"}},
+			expectedError: "the ReplicatedCollapsingMergeTree engine takes 1 parameters, after the optional ZooKeeper path and replica name, got 2",
+		},
+		{table: TableResource{Engine: "Distributed", EngineParams: []string{"cluster", "default", "events", "rand()"}}},
+		{
+			table:         TableResource{Engine: "Distributed", EngineParams: []string{"cluster", "default"}},
+			expectedError: "the Distributed engine takes 3 to 5 parameters, got 2",
+		},
+		{
+			table:         TableResource{Engine: "Distributed", EngineParams: []string{"cluster", "default", "events"}, TTL: map[string]string{"created_at + INTERVAL 1 DAY": "DELETE"}},
+			expectedError: "the Distributed engine doesn't support ttl",
+		},
+		{
+			table:         TableResource{Engine: "Memory", OrderBy: []string{"id"}},
+			expectedError: "the Memory engine doesn't support order_by",
+		},
+		{table: TableResource{Engine: "Kafka", Settings: map[string]string{"kafka_topic_list": "events"}}},
+		{
+			table:         TableResource{Engine: "ReplicatedMergeTree"},
+			expectedError: `the ReplicatedMergeTree engine requires order_by or primary_key, e.g. order_by = ["tuple()"] for no sorting key`,
+		},
+		{table: TableResource{Engine: "MergeTree", OrderBy: []string{"tuple()"}}},
+		{table: TableResource{Engine: "MergeTree", PrimaryKey: []string{"id"}}},
+		// The engines missing from the registry are only checked by the server
+		{table: TableResource{Engine: "EmbeddedRocksDB", PrimaryKey: []string{"id"}, Settings: map[string]string{"optimize_for_bulk_insert": "1"}}},
+		{table: TableResource{Engine: "MySQL", EngineParams: []string{"'mysql:3306'", "'shop'", "'orders'", "'reader'", "'secret'"}}},
+	}
+
+	for _, tt := range testCases {
+		err := tt.table.ValidateEngine()
+		switch {
+		case tt.expectedError == "" && err != nil:
+			t.Errorf("ValidateEngine(%s) error = %v", tt.table.Engine, err)
+		case tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError):
+			t.Errorf("ValidateEngine(%s) error = %v, expected %q", tt.table.Engine, err, tt.expectedError)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetEngineParams(t *testing.T) {
+	testCases := []struct {
+		engineFull     string
+		expectedParams []string
+	}{
+		{engineFull: "MergeTree ORDER BY event_date", expectedParams: nil},
+		{
+			engineFull:     "Distributed('cluster', 'analytics', 'events', rand())",
+			expectedParams: []string{"'cluster'", "'analytics'", "'events'", "rand()"},
+		},
+		{
+			engineFull:     "ReplicatedMergeTree('/clickhouse/tables/{shard}/events, (copy)', '{replica}') ORDER BY event_date",
+			expectedParams: []string{"'/clickhouse/tables/{shard}/events, (copy)'", "'{replica}'"},
+		},
+	}
+
+	for _, tt := range testCases {
+		if params := GetEngineParams(tt.engineFull); !reflect.DeepEqual(params, tt.expectedParams) {
+			t.Errorf("GetEngineParams(%q) = %q, expected %q", tt.engineFull, params, tt.expectedParams)
+		}
+	}
+}
+
+func TestTableResourceDifferences(t *testing.T) {
+	table := TableResource{
+		Engine:       "ReplicatedMergeTree",
+		EngineParams: []string{"'/clickhouse/tables/{shard}/events'", "'{replica}'"},
+		OrderBy:      []string{"event_date", "event_type"},
+		PartitionBy:  []PartitionByResource{{By: "event_date", PartitionFunction: "toYYYYMM"}},
+		TTL:          map[string]string{"event_date + toIntervalMonth(1)": "DELETE"},
+		Settings:     map[string]string{"merge_with_ttl_timeout": "3600"},
+		Columns:      []ColumnDefinition{{Name: "event_date", Type: "Date"}, {Name: "event_type", Type: "Int32"}},
+	}
+	existing := func(engineFull string, partitionKey string, primaryKey string) *CHTable {
+		return &CHTable{
+			Engine:       "ReplicatedMergeTree",
+			EngineFull:   engineFull,
+			SortingKey:   "event_date, event_type",
+			PartitionKey: partitionKey,
+			PrimaryKey:   primaryKey,
+			Columns:      []CHColumn{{Name: "event_date", Type: "Date"}, {Name: "event_type", Type: "Int32"}},
+		}
+	}
+	const engine = "ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')"
+	const clauses = " PARTITION BY toYYYYMM(event_date) ORDER BY (event_date, event_type) TTL event_date + toIntervalMonth(1)"
+
+	testCases := []struct {
+		name                string
+		existing            *CHTable
+		expectedDifferences []string
+	}{
+		{
+			name:     "identical",
+			existing: existing(engine+clauses+" SETTINGS merge_with_ttl_timeout = 3600, index_granularity = 8192", "toYYYYMM(event_date)", "event_date, event_type"),
+		},
+		{
+			name:                "engine params",
+			existing:            existing("ReplicatedMergeTree('/clickhouse/tables/{shard}/other', '{replica}')"+clauses+" SETTINGS merge_with_ttl_timeout = 3600", "toYYYYMM(event_date)", "event_date, event_type"),
+			expectedDifferences: []string{"engine params are (/clickhouse/tables/{shard}/other) instead of (/clickhouse/tables/{shard}/events)"},
+		},
+		{
+			name:                "partition by",
+			existing:            existing(engine+clauses+" SETTINGS merge_with_ttl_timeout = 3600", "event_date", "event_date, event_type"),
+			expectedDifferences: []string{"partition by is (event_date) instead of (toYYYYMM(event_date))"},
+		},
+		{
+			name:                "primary key",
+			existing:            existing(engine+clauses+" SETTINGS merge_with_ttl_timeout = 3600", "toYYYYMM(event_date)", "event_date"),
+			expectedDifferences: []string{"primary key is (event_date) instead of (event_date, event_type)"},
+		},
+		{
+			name:                "ttl",
+			existing:            existing(engine+" PARTITION BY toYYYYMM(event_date) ORDER BY (event_date, event_type) SETTINGS merge_with_ttl_timeout = 3600", "toYYYYMM(event_date)", "event_date, event_type"),
+			expectedDifferences: []string{"ttl is () instead of (event_date + toIntervalMonth(1) DELETE)"},
+		},
+		{
+			name:     "settings",
+			existing: existing(engine+clauses+" SETTINGS merge_with_ttl_timeout = 60, index_granularity = 1024", "toYYYYMM(event_date)", "event_date, event_type"),
+			expectedDifferences: []string{
+				`setting merge_with_ttl_timeout is "60" instead of "3600"`,
+				"setting index_granularity is not configured",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		if differences := table.Differences(tt.existing); !reflect.DeepEqual(differences, tt.expectedDifferences) {
+			t.Errorf("%s: Differences() = %q, expected %q", tt.name, differences, tt.expectedDifferences)
+		}
+	}
+}
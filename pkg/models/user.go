@@ -12,6 +12,7 @@ type CHUser struct {
 
 type UserResource struct {
 	Name     string
+	Cluster  string
 	Password string
 	Roles    *schema.Set
 }
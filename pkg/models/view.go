@@ -1,6 +1,11 @@
 package models
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+import (
+	"fmt"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
 
 type ViewResource struct {
 	Database     string
@@ -10,6 +15,7 @@ type ViewResource struct {
 	Materialized bool
 	ToTable      string
 	Comment      string
+	CreateMode   string
 }
 
 type CHView struct {
@@ -38,3 +44,18 @@ func (t *ViewResource) Validate() diag.Diagnostics {
 
 	return diags
 }
+
+// Differences lists what differs between the view and an existing one, in order to adopt it
+func (t *ViewResource) Differences(existing *ViewResource) []string {
+	var differences []string
+	if t.Materialized != existing.Materialized {
+		differences = append(differences, fmt.Sprintf("materialized is %t instead of %t", existing.Materialized, t.Materialized))
+	}
+	if common.NormalizeQuery(t.Query) != common.NormalizeQuery(existing.Query) {
+		differences = append(differences, fmt.Sprintf("query is %q instead of %q", common.NormalizeQuery(existing.Query), common.NormalizeQuery(t.Query)))
+	}
+	if t.Comment != existing.Comment {
+		differences = append(differences, fmt.Sprintf("comment is %q instead of %q", existing.Comment, t.Comment))
+	}
+	return differences
+}
@@ -0,0 +1,354 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	ProtocolNative = "native"
+	ProtocolHTTP   = "http"
+)
+
+const (
+	ConnOpenInOrder    = "in_order"
+	ConnOpenRoundRobin = "round_robin"
+	ConnOpenRandom     = "random"
+)
+
+var connOpenStrategies = map[string]clickhouse.ConnOpenStrategy{
+	ConnOpenInOrder:    clickhouse.ConnOpenInOrder,
+	ConnOpenRoundRobin: clickhouse.ConnOpenRoundRobin,
+	ConnOpenRandom:     clickhouse.ConnOpenRandom,
+}
+
+// dialRetryBackoff is the pause between two passes over the endpoints
+var dialRetryBackoff = time.Second
+
+// clickhouseOptions builds the clickhouse-go connection options from the provider configuration
+func clickhouseOptions(d *schema.ResourceData) (*clickhouse.Options, error) {
+	host := d.Get("host").(string)
+	port := d.Get("port").(int)
+	username := d.Get("username").(string)
+	password := d.Get("password").(string)
+	secure := d.Get("secure").(bool)
+	protocol := d.Get("protocol").(string)
+	httpPath := d.Get("http_path").(string)
+	httpHeaders := common.MapInterfaceToMapOfString(d.Get("http_headers").(map[string]interface{}))
+	httpProxyURL := d.Get("http_proxy_url").(string)
+	connOpenStrategy := d.Get("connection_open_strategy").(string)
+	dialTimeout := d.Get("dial_timeout").(int)
+	dialRetries := d.Get("dial_retries").(int)
+
+	addresses := []string{fmt.Sprintf("%s:%d", host, port)}
+	if endpoints := d.Get("endpoints").([]interface{}); len(endpoints) > 0 {
+		addresses = make([]string, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			endpointMap := endpoint.(map[string]interface{})
+			endpointPort := endpointMap["port"].(int)
+			if endpointPort == 0 {
+				endpointPort = port
+			}
+			addresses = append(addresses, net.JoinHostPort(endpointMap["host"].(string), strconv.Itoa(endpointPort)))
+		}
+	}
+
+	strategy, ok := connOpenStrategies[connOpenStrategy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported connection_open_strategy %q", connOpenStrategy)
+	}
+
+	var TLSConfig *tls.Config
+	// To use TLS it's necessary to set the TLSConfig field as not nil
+	if secure {
+		TLSConfig = &tls.Config{
+			InsecureSkipVerify: false,
+		}
+	}
+	if tlsBlock := d.Get("tls").([]interface{}); len(tlsBlock) > 0 {
+		var err error
+		// An empty block (tls {}) is read as a nil element
+		tlsMap, _ := tlsBlock[0].(map[string]interface{})
+		if TLSConfig, err = tlsConfig(tlsMap); err != nil {
+			return nil, err
+		}
+	}
+
+	options := &clickhouse.Options{
+		Addr: addresses,
+		Auth: clickhouse.Auth{
+			Username: username,
+			Password: password,
+		},
+		Debug: common.DebugEnabled,
+		Debugf: func(format string, v ...any) {
+			if common.DebugEnabled {
+				fmt.Printf(format, v...)
+			}
+		},
+		Settings:         clickhouseSettings(d),
+		TLS:              TLSConfig,
+		ConnOpenStrategy: strategy,
+		DialTimeout:      time.Duration(dialTimeout) * time.Second,
+		DialStrategy:     retryDialStrategy(dialRetries),
+	}
+
+	switch protocol {
+	case ProtocolHTTP:
+		options.Protocol = clickhouse.HTTP
+		options.HttpUrlPath = httpPath
+		if len(httpHeaders) > 0 {
+			options.HttpHeaders = httpHeaders
+		}
+		if httpProxyURL != "" {
+			proxyURL, err := url.Parse(httpProxyURL)
+			if err != nil {
+				return nil, fmt.Errorf("parsing http_proxy_url: %w", err)
+			}
+			if proxyURL.Scheme != "http" || proxyURL.Host == "" {
+				return nil, fmt.Errorf("http_proxy_url must be an absolute http:// URL, got %q", httpProxyURL)
+			}
+			// The HTTP transport of clickhouse-go always honours the proxy of the environment
+			// and would stack it on top of the tunnel, so both can't be used together
+			for _, address := range addresses {
+				envProxy, err := proxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: httpScheme(TLSConfig), Host: address}})
+				if err != nil {
+					return nil, fmt.Errorf("reading the proxy of the environment: %w", err)
+				}
+				if envProxy != nil {
+					return nil, fmt.Errorf("http_proxy_url can't be combined with the proxy %s set in the environment for %s, unset HTTP_PROXY and HTTPS_PROXY or list the host in NO_PROXY", envProxy.Redacted(), address)
+				}
+			}
+			options.DialContext = proxyDialer(proxyURL)
+		}
+	case ProtocolNative:
+		if httpPath != "" || len(httpHeaders) > 0 || httpProxyURL != "" {
+			return nil, fmt.Errorf("http_path, http_headers and http_proxy_url can only be used with the %q protocol", ProtocolHTTP)
+		}
+		options.Protocol = clickhouse.Native
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+
+	return options, nil
+}
+
+// unknownAttributes lists the provider attributes whose value is not known yet, e.g. because they
+// reference a resource which is created in the same run
+func unknownAttributes(d *schema.ResourceData) []string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	var unknown []string
+	for name := range config.Type().AttributeTypes() {
+		if !config.GetAttr(name).IsWhollyKnown() {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// clickhouseSettings merges the provider `settings` with the default ones
+func clickhouseSettings(d *schema.ResourceData) clickhouse.Settings {
+	settings := clickhouse.Settings{
+		"max_execution_time": 300,
+	}
+	for key, value := range d.Get("settings").(map[string]interface{}) {
+		settings[key] = value.(string)
+	}
+	return settings
+}
+
+// timeoutLimits reads the sdk.TimeoutSettings set in the provider `settings`, in seconds. Unlike
+// the default max_execution_time, they bound the timeouts of the resources.
+func timeoutLimits(d *schema.ResourceData) (map[string]int, error) {
+	settings := d.Get("settings").(map[string]interface{})
+	limits := map[string]int{}
+	for _, name := range sdk.TimeoutSettings {
+		value, ok := settings[name]
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(value.(string), 64)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("settings.%s must be a number of seconds, got %q", name, value)
+		}
+		limits[name] = int(math.Ceil(seconds))
+	}
+	return limits, nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsConfig builds the TLS configuration from the provider `tls` block
+func tlsConfig(tlsMap map[string]interface{}) (*tls.Config, error) {
+	getString := func(key string) string {
+		value, _ := tlsMap[key].(string)
+		return value
+	}
+
+	config := &tls.Config{
+		ServerName:         getString("server_name"),
+		InsecureSkipVerify: tlsMap["insecure_skip_verify"] == true,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if minVersion := getString("min_version"); minVersion != "" {
+		version, ok := tlsVersions[minVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls min_version %q", minVersion)
+		}
+		config.MinVersion = version
+	}
+
+	caCertPEM := []byte(getString("ca_cert_pem"))
+	if caCertFile := getString("ca_cert_file"); caCertFile != "" {
+		content, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading tls ca_cert_file: %w", err)
+		}
+		caCertPEM = content
+	}
+	if len(caCertPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("no valid PEM certificate found in the tls CA bundle")
+		}
+		config.RootCAs = pool
+	}
+
+	clientCertPEM := []byte(getString("client_cert"))
+	clientKeyPEM := []byte(getString("client_key"))
+	if clientCertFile := getString("client_cert_file"); clientCertFile != "" {
+		content, err := os.ReadFile(clientCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading tls client_cert_file: %w", err)
+		}
+		clientCertPEM = content
+	}
+	if clientKeyFile := getString("client_key_file"); clientKeyFile != "" {
+		content, err := os.ReadFile(clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading tls client_key_file: %w", err)
+		}
+		clientKeyPEM = content
+	}
+	if len(clientCertPEM) > 0 || len(clientKeyPEM) > 0 {
+		certificate, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("loading tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// retryDialStrategy wraps the clickhouse-go dial strategy, which already moves to the next
+// endpoint when one is unavailable, to go over the whole endpoint list again when all of
+// them failed, e.g. while a replica is being restarted.
+func retryDialStrategy(retries int) func(ctx context.Context, connID int, options *clickhouse.Options, dial clickhouse.Dial) (clickhouse.DialResult, error) {
+	return func(ctx context.Context, connID int, options *clickhouse.Options, dial clickhouse.Dial) (clickhouse.DialResult, error) {
+		result, err := clickhouse.DefaultDialStrategy(ctx, connID, options, dial)
+		for attempt := 1; err != nil && attempt <= retries; attempt++ {
+			tflog.Debug(ctx, fmt.Sprintf("no Clickhouse endpoint available (%v), retrying %d/%d", err, attempt, retries))
+			select {
+			case <-ctx.Done():
+				return result, err
+			case <-time.After(dialRetryBackoff):
+			}
+			result, err = clickhouse.DefaultDialStrategy(ctx, connID, options, dial)
+		}
+		return result, err
+	}
+}
+
+// proxyFromEnvironment is the proxy lookup of the clickhouse-go HTTP transport, replaced in tests
+var proxyFromEnvironment = http.ProxyFromEnvironment
+
+// httpScheme returns the scheme of the requests sent to the Clickhouse HTTP interface
+func httpScheme(TLSConfig *tls.Config) string {
+	if TLSConfig != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// proxyDialer returns a dial function opening a tunnel to the target address through
+// the given HTTP proxy with a CONNECT request. TLS, when enabled, is negotiated by the
+// HTTP transport on top of the tunnel, so the proxy never sees the traffic in clear.
+func proxyDialer(proxyURL *url.URL) func(ctx context.Context, addr string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+		if err != nil {
+			return nil, fmt.Errorf("dialing proxy %s: %w", proxyURL.Host, err)
+		}
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: make(http.Header),
+		}
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+			req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		if err := req.Write(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("writing CONNECT request to proxy: %w", err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("reading CONNECT response from proxy: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			conn.Close()
+			return nil, fmt.Errorf("proxy refused tunnel to %s: %s", addr, resp.Status)
+		}
+
+		// Clear the handshake deadline, the HTTP transport manages its own timeouts
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
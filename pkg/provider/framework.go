@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/datasources"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/functions"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/resources"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// frameworkProvider serves the resources and data sources ported to the plugin framework. It is
+// muxed with the SDKv2 provider, which owns the provider configuration: both servers must expose
+// the same provider schema, and the Clickhouse client is the one configured by the SDKv2 provider.
+type frameworkProvider struct {
+	version     string
+	sdkProvider *schema.Provider
+}
+
+var _ fwprovider.ProviderWithFunctions = &frameworkProvider{}
+
+func newFrameworkProvider(sdkProvider *schema.Provider, version string) func() fwprovider.Provider {
+	return func() fwprovider.Provider {
+		return &frameworkProvider{
+			version:     version,
+			sdkProvider: sdkProvider,
+		}
+	}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ fwprovider.MetadataRequest, resp *fwprovider.MetadataResponse) {
+	resp.TypeName = "clickhouse"
+	resp.Version = p.version
+}
+
+// Schema converts the SDKv2 provider schema, the only one maintained
+func (p *frameworkProvider) Schema(ctx context.Context, _ fwprovider.SchemaRequest, resp *fwprovider.SchemaResponse) {
+	sdkSchema, err := schema.NewGRPCProviderServer(p.sdkProvider).GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read the provider schema", err.Error())
+		return
+	}
+	attributes, blocks, err := convertProviderBlock(sdkSchema.Provider.Block)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to convert the provider schema", err.Error())
+		return
+	}
+	resp.Schema = fwschema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+}
+
+// Configure shares the client of the SDKv2 provider, which the mux server configures first
+func (p *frameworkProvider) Configure(_ context.Context, _ fwprovider.ConfigureRequest, resp *fwprovider.ConfigureResponse) {
+	client, ok := p.sdkProvider.Meta().(*sdk.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Provider not configured", "The Clickhouse client of the SDKv2 provider isn't configured.")
+		return
+	}
+	resp.ResourceData = client
+	resp.DataSourceData = client
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		resources.NewDbResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		datasources.NewDbDataSource,
+		datasources.NewDbsDataSource,
+	}
+}
+
+func (p *frameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		functions.NewQuoteStringFunction,
+		functions.NewQuoteIdentifierFunction,
+		functions.NewNormalizeQueryFunction,
+		functions.NewReplicatedPathFunction,
+		functions.NewParseTableIDFunction,
+	}
+}
+
+func convertProviderBlock(block *tfprotov5.SchemaBlock) (map[string]fwschema.Attribute, map[string]fwschema.Block, error) {
+	attributes := map[string]fwschema.Attribute{}
+	for _, a := range block.Attributes {
+		attribute, err := convertProviderAttribute(a)
+		if err != nil {
+			return nil, nil, fmt.Errorf("attribute %s: %v", a.Name, err)
+		}
+		attributes[a.Name] = attribute
+	}
+
+	blocks := map[string]fwschema.Block{}
+	for _, b := range block.BlockTypes {
+		nestedAttributes, nestedBlocks, err := convertProviderBlock(b.Block)
+		if err != nil {
+			return nil, nil, fmt.Errorf("block %s: %v", b.TypeName, err)
+		}
+		nestedObject := fwschema.NestedBlockObject{
+			Attributes: nestedAttributes,
+			Blocks:     nestedBlocks,
+		}
+		description, markdownDescription := descriptions(b.Block.Description, b.Block.DescriptionKind)
+		switch b.Nesting {
+		case tfprotov5.SchemaNestedBlockNestingModeList:
+			blocks[b.TypeName] = fwschema.ListNestedBlock{
+				NestedObject:        nestedObject,
+				Description:         description,
+				MarkdownDescription: markdownDescription,
+			}
+		case tfprotov5.SchemaNestedBlockNestingModeSet:
+			blocks[b.TypeName] = fwschema.SetNestedBlock{
+				NestedObject:        nestedObject,
+				Description:         description,
+				MarkdownDescription: markdownDescription,
+			}
+		default:
+			return nil, nil, fmt.Errorf("block %s: unsupported nesting mode %v", b.TypeName, b.Nesting)
+		}
+	}
+
+	return attributes, blocks, nil
+}
+
+func convertProviderAttribute(a *tfprotov5.SchemaAttribute) (fwschema.Attribute, error) {
+	description, markdownDescription := descriptions(a.Description, a.DescriptionKind)
+	deprecationMessage := ""
+	if a.Deprecated {
+		deprecationMessage = "Deprecated"
+	}
+
+	switch {
+	case a.Type.Is(tftypes.String):
+		return fwschema.StringAttribute{Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	case a.Type.Is(tftypes.Number):
+		return fwschema.NumberAttribute{Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	case a.Type.Is(tftypes.Bool):
+		return fwschema.BoolAttribute{Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	}
+
+	attrType, err := convertType(a.Type)
+	if err != nil {
+		return nil, err
+	}
+	switch t := attrType.(type) {
+	case types.MapType:
+		return fwschema.MapAttribute{ElementType: t.ElemType, Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	case types.ListType:
+		return fwschema.ListAttribute{ElementType: t.ElemType, Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	case types.SetType:
+		return fwschema.SetAttribute{ElementType: t.ElemType, Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecationMessage, Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", a.Type)
+	}
+}
+
+func convertType(t tftypes.Type) (attr.Type, error) {
+	switch {
+	case t.Is(tftypes.String):
+		return types.StringType, nil
+	case t.Is(tftypes.Number):
+		return types.NumberType, nil
+	case t.Is(tftypes.Bool):
+		return types.BoolType, nil
+	case t.Is(tftypes.Map{}):
+		elemType, err := convertType(t.(tftypes.Map).ElementType)
+		return types.MapType{ElemType: elemType}, err
+	case t.Is(tftypes.List{}):
+		elemType, err := convertType(t.(tftypes.List).ElementType)
+		return types.ListType{ElemType: elemType}, err
+	case t.Is(tftypes.Set{}):
+		elemType, err := convertType(t.(tftypes.Set).ElementType)
+		return types.SetType{ElemType: elemType}, err
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func descriptions(description string, kind tfprotov5.StringKind) (string, string) {
+	if kind == tfprotov5.StringKindMarkdown {
+		return "", description
+	}
+	return description, ""
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestMuxServerSchema(t *testing.T) {
+	ctx := context.Background()
+	muxServer, err := MuxServer(ctx, New("dev")(), "dev")
+	if err != nil {
+		t.Fatalf("MuxServer() error = %v", err)
+	}
+
+	// The mux server reports an error when the provider schemas of the servers differ
+	resp, err := muxServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema() error = %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("GetProviderSchema() diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	for _, name := range []string{"clickhouse_db", "clickhouse_table", "clickhouse_view", "clickhouse_role", "clickhouse_user"} {
+		if _, ok := resp.ResourceSchemas[name]; !ok {
+			t.Errorf("resource %s is not served", name)
+		}
+	}
+	if _, ok := resp.DataSourceSchemas["clickhouse_dbs"]; !ok {
+		t.Errorf("data source clickhouse_dbs is not served")
+	}
+	for _, name := range []string{"quote_string", "quote_identifier", "normalize_query", "replicated_path", "parse_table_id"} {
+		if _, ok := resp.Functions[name]; !ok {
+			t.Errorf("function %s is not served", name)
+		}
+	}
+}
+
+func TestMuxServerDbStateCompatibility(t *testing.T) {
+	ctx := context.Background()
+	muxServer, err := MuxServer(ctx, New("dev")(), "dev")
+	if err != nil {
+		t.Fatalf("MuxServer() error = %v", err)
+	}
+
+	// State written by the SDKv2 clickhouse_db resource
+	rawState := `{
+		"id": "cluster:events",
+		"query_settings": null,
+		"create_mode": null,
+		"cluster": "cluster",
+		"name": "events",
+		"engine": "Atomic",
+		"data_path": "/var/lib/clickhouse/store/",
+		"metadata_path": "/var/lib/clickhouse/store/b13/b13bbcb5-85c6-4f54-8d8f-3f8d0e1c0a47/",
+		"uuid": "b13bbcb5-85c6-4f54-8d8f-3f8d0e1c0a47",
+		"comment": ""
+	}`
+	resp, err := muxServer().UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "clickhouse_db",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil {
+		t.Fatalf("UpgradeResourceState() error = %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("UpgradeResourceState() diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	schemaResp, err := muxServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema() error = %v", err)
+	}
+	state, err := resp.UpgradedState.Unmarshal(schemaResp.ResourceSchemas["clickhouse_db"].ValueType())
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("As() error = %v", err)
+	}
+	// The engine name of the SDKv2 state is upgraded into the engine attributes
+	var engine map[string]tftypes.Value
+	if err := attributes["engine"].As(&engine); err != nil {
+		t.Fatalf("engine: As() error = %v", err)
+	}
+	attributes["engine.name"] = engine["name"]
+	for name, expected := range map[string]string{"id": "cluster:events", "cluster": "cluster", "name": "events", "engine.name": "Atomic"} {
+		var value string
+		if err := attributes[name].As(&value); err != nil {
+			t.Fatalf("%s: As() error = %v", name, err)
+		}
+		if value != expected {
+			t.Errorf("%s = %q, expected %q", name, value, expected)
+		}
+	}
+}
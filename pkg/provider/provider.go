@@ -2,16 +2,16 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
-	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/datasources"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/resources"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func init() {
@@ -53,82 +53,259 @@ func New(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("TF_VAR_CLICKHOUSE_PASSWORD", ""),
 				},
 				"host": {
-					Description: "Clickhouse server URL",
+					Description: "Clickhouse server URL, ignored when `endpoints` are provided",
 					Type:        schema.TypeString,
 					Required:    true,
 					DefaultFunc: schema.EnvDefaultFunc("TF_VAR_CLICKHOUSE_HOST", "127.0.0.1"),
 				},
 				"port": {
-					Description: "Clickhouse server native protocol port (TCP)",
+					Description: "Clickhouse server port, either the native protocol port (TCP) or the HTTP interface port depending on `protocol`",
 					Type:        schema.TypeInt,
 					Required:    true,
 					DefaultFunc: schema.EnvDefaultFunc("TF_VAR_CLICKHOUSE_PORT", 9000),
 				},
+				"endpoints": {
+					Description: "Clickhouse servers to connect to, e.g. every replica of a cluster. When provided they replace `host`, and the next endpoint is tried when one is unavailable",
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"host": {
+								Description: "Clickhouse server host",
+								Type:        schema.TypeString,
+								Required:    true,
+							},
+							"port": {
+								Description: "Clickhouse server port, defaults to the provider `port`",
+								Type:        schema.TypeInt,
+								Optional:    true,
+							},
+						},
+					},
+				},
+				"connection_open_strategy": {
+					Description:  "Order in which `endpoints` are tried when opening a connection: `in_order` (failover), `round_robin` or `random`",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      ConnOpenInOrder,
+					ValidateFunc: validation.StringInSlice([]string{ConnOpenInOrder, ConnOpenRoundRobin, ConnOpenRandom}, false),
+				},
+				"dial_timeout": {
+					Description:  "Timeout in seconds to open a connection to a single endpoint",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"dial_retries": {
+					Description:  "Number of additional attempts over all the endpoints when none of them accepts the connection",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      2,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_retries": {
+					Description:  "Number of additional attempts for idempotent statements failing with a transient error (network error, distributed DDL timeout, replica in read-only mode...)",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      3,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"distributed_ddl_wait_timeout": {
+					Description:  "Seconds to wait, from the start of an ON CLUSTER statement, for the hosts which didn't finish it within `distributed_ddl_task_timeout`, by following them in `system.distributed_ddl_queue`. `0` fails right away with the status of every host",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"retry_backoff": {
+					Description:  "Delay in seconds before retrying a statement, doubled after every attempt and randomized to spread retries",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"dry_run": {
+					Description: "Record the statements modifying Clickhouse in `sql_output_file` instead of executing them, reads still hit the server. Useful to review the exact DDL of a plan before applying it",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+				},
+				"sql_output_file": {
+					Description: "File the statements are appended to when `dry_run` is enabled, each one terminated by a semicolon. When not set, the statements are only logged",
+					Type:        schema.TypeString,
+					Optional:    true,
+				},
+				"audit_log_file": {
+					Description: "File receiving a JSON line for every statement modifying Clickhouse, with its `query_id`, duration, configured endpoints, outcome and the resource it was executed for",
+					Type:        schema.TypeString,
+					Optional:    true,
+				},
+				"create_mode": {
+					Description: "How databases, tables and views are created unless their own `create_mode` says otherwise: `create`, `create_or_replace` (ignored for databases and materialized views, which are created with a plain `CREATE`), `create_if_not_exists` or `adopt`, which takes over existing objects identical to the configuration and fails if they differ. When not set, the deprecated `TF_VAR_CREATE_OR_REPLACE` and `TF_VAR_CREATE_IF_NOT_EXISTS` env vars are used for databases and tables",
+					Type:        schema.TypeString,
+					Optional:    true,
+					ValidateFunc: validation.StringInSlice([]string{
+						common.CreateModeCreate,
+						common.CreateModeOrReplace,
+						common.CreateModeIfNotExists,
+						common.CreateModeAdopt,
+					}, false),
+				},
 				"secure": {
-					Description: "Clickhouse secure connection",
+					Description: "Clickhouse secure connection (TLS), implied when a `tls` block is provided",
 					Type:        schema.TypeBool,
 					Optional:    true,
 					Default:     false,
 				},
+				"tls": {
+					Description: "TLS configuration, a single block. Setting it enables secure connections",
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"ca_cert_file": {
+								Description:   "Path to a PEM bundle of the certificate authorities trusted to verify the server certificate, instead of the system ones",
+								Type:          schema.TypeString,
+								Optional:      true,
+								ConflictsWith: []string{"tls.0.ca_cert_pem"},
+							},
+							"ca_cert_pem": {
+								Description:   "PEM bundle of the certificate authorities trusted to verify the server certificate, instead of the system ones",
+								Type:          schema.TypeString,
+								Optional:      true,
+								ConflictsWith: []string{"tls.0.ca_cert_file"},
+							},
+							"client_cert": {
+								Description:   "PEM encoded client certificate presented to the server",
+								Type:          schema.TypeString,
+								Optional:      true,
+								ConflictsWith: []string{"tls.0.client_cert_file"},
+								RequiredWith:  []string{"tls.0.client_key"},
+							},
+							"client_key": {
+								Description:   "PEM encoded private key of `client_cert`",
+								Type:          schema.TypeString,
+								Optional:      true,
+								Sensitive:     true,
+								ConflictsWith: []string{"tls.0.client_key_file"},
+								RequiredWith:  []string{"tls.0.client_cert"},
+							},
+							"client_cert_file": {
+								Description:   "Path to a PEM encoded client certificate presented to the server",
+								Type:          schema.TypeString,
+								Optional:      true,
+								ConflictsWith: []string{"tls.0.client_cert"},
+								RequiredWith:  []string{"tls.0.client_key_file"},
+							},
+							"client_key_file": {
+								Description:   "Path to the PEM encoded private key of `client_cert_file`",
+								Type:          schema.TypeString,
+								Optional:      true,
+								ConflictsWith: []string{"tls.0.client_key"},
+								RequiredWith:  []string{"tls.0.client_cert_file"},
+							},
+							"server_name": {
+								Description: "Server name used to verify the server certificate, when it differs from the host (e.g. behind a load balancer)",
+								Type:        schema.TypeString,
+								Optional:    true,
+							},
+							"insecure_skip_verify": {
+								Description: "Skip the verification of the server certificate. Only meant for testing",
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+							},
+							"min_version": {
+								Description:  "Minimum TLS version accepted: `1.0`, `1.1`, `1.2` or `1.3`",
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      "1.2",
+								ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+							},
+						},
+					},
+				},
+				"settings": {
+					Description: "Clickhouse settings applied to every statement, e.g. `distributed_ddl_task_timeout`, `alter_sync` or `mutations_sync`. `max_execution_time` defaults to 300 seconds, replaced by the `timeouts` of the resources. When set here, `max_execution_time` and `distributed_ddl_task_timeout` must be numbers of seconds and stay the upper bound of the statements: the `timeouts` of the resources only shorten them, unless they are 0 (unlimited)",
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"protocol": {
+					Description:  "Protocol used to talk to Clickhouse, either `native` (TCP) or `http`",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      ProtocolNative,
+					ValidateFunc: validation.StringInSlice([]string{ProtocolNative, ProtocolHTTP}, false),
+				},
+				"http_path": {
+					Description: "URL path of the Clickhouse HTTP interface, when it is not served at the root (e.g. behind a load balancer). Only used with the `http` protocol",
+					Type:        schema.TypeString,
+					Optional:    true,
+				},
+				"http_headers": {
+					Description: "Additional headers sent with every request. Only used with the `http` protocol",
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"http_proxy_url": {
+					Description: "URL of an HTTP proxy used to reach the Clickhouse HTTP interface, the connection is tunneled with `CONNECT`. Can't be combined with a proxy set by the `HTTP_PROXY` or `HTTPS_PROXY` environment variables for the same hosts. Only used with the `http` protocol",
+					Type:        schema.TypeString,
+					Optional:    true,
+				},
 			},
-			DataSourcesMap: map[string]*schema.Resource{
-				"clickhouse_dbs": datasources.DataSourceDbs(),
-			},
+			// clickhouse_db and clickhouse_dbs are served by the plugin framework provider, see MuxServer
 			ResourcesMap: map[string]*schema.Resource{
-				"clickhouse_db":    resources.ResourceDb(),
 				"clickhouse_table": resources.ResourceTable(),
 				"clickhouse_view":  resources.ResourceView(),
 				"clickhouse_role":  resources.ResourceRole(),
 				"clickhouse_user":  resources.ResourceUser(),
 			},
-			ConfigureContextFunc: configure(),
+			ConfigureContextFunc: configure(version),
 		}
 	}
 }
 
-func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
+func configure(version string) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
-		host := d.Get("host").(string)
-		port := d.Get("port").(int)
-		username := d.Get("username").(string)
-		password := d.Get("password").(string)
-		secure := d.Get("secure").(bool)
+		var diags diag.Diagnostics
 
-		var TLSConfig *tls.Config
-		// To use TLS it's necessary to set the TLSConfig field as not nil
-		if secure {
-			TLSConfig = &tls.Config{
-				InsecureSkipVerify: false,
-			}
+		client := &sdk.Client{
+			DefaultCluster:  d.Get("default_cluster").(string),
+			CreateMode:      d.Get("create_mode").(string),
+			MaxRetries:      d.Get("max_retries").(int),
+			RetryBackoff:    time.Duration(d.Get("retry_backoff").(int)) * time.Second,
+			DryRun:          d.Get("dry_run").(bool),
+			SQLOutputFile:   d.Get("sql_output_file").(string),
+			AuditLogFile:    d.Get("audit_log_file").(string),
+			ProviderVersion: version,
+
+			DistributedDDLWaitTimeout: time.Duration(d.Get("distributed_ddl_wait_timeout").(int)) * time.Second,
 		}
-		conn, err := clickhouse.Open(&clickhouse.Options{
-			Addr: []string{fmt.Sprintf("%s:%d", host, port)},
-			Auth: clickhouse.Auth{
-				Username: username,
-				Password: password,
-			},
-			Debug: common.DebugEnabled,
-			Debugf: func(format string, v ...any) {
-				if common.DebugEnabled {
-					fmt.Printf(format, v...)
-				}
-			},
-			Settings: clickhouse.Settings{
-				"max_execution_time": 300,
-			},
-			TLS: TLSConfig,
-		})
 
-		var diags diag.Diagnostics
+		// While planning, the connection attributes may reference resources which are not created
+		// yet: the client is still returned so that the plan succeeds, but any statement fails
+		if unknown := unknownAttributes(d); len(unknown) > 0 {
+			client.ConfigError = fmt.Errorf("the Clickhouse connection can't be opened: the provider attributes %s are not known yet, they depend on resources which are not created", strings.Join(unknown, ", "))
+			return client, diags
+		}
 
+		options, err := clickhouseOptions(d)
 		if err != nil {
-			return nil, diag.FromErr(fmt.Errorf("error connecting to clickhouse: %v", err))
+			return nil, diag.FromErr(fmt.Errorf("invalid provider configuration: %w", err))
 		}
-
-		if err := conn.Ping(ctx); err != nil {
-			return nil, diag.FromErr(fmt.Errorf("ping clickhouse database: %w", err))
+		client.Options = options
+		if client.TimeoutLimits, err = timeoutLimits(d); err != nil {
+			return nil, diag.FromErr(fmt.Errorf("invalid provider configuration: %w", err))
 		}
 
-		return &sdk.Client{Conn: conn}, diags
+		return client, diags
 	}
 }
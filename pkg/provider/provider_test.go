@@ -1,9 +1,33 @@
 package provider
 
 import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 // providerFactories are used to instantiate a provider during acceptance testing.
@@ -21,3 +45,449 @@ func TestProvider(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 }
+
+func TestClickhouseOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      map[string]interface{}
+		expectError bool
+		check       func(t *testing.T, options *clickhouse.Options)
+	}{
+		{
+			name:   "native protocol by default",
+			config: map[string]interface{}{"host": "localhost", "port": 9000},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				if options.Protocol != clickhouse.Native {
+					t.Errorf("expected native protocol, got %v", options.Protocol)
+				}
+				if options.Addr[0] != "localhost:9000" {
+					t.Errorf("expected address localhost:9000, got %v", options.Addr)
+				}
+			},
+		},
+		{
+			name: "http protocol",
+			config: map[string]interface{}{
+				"host":         "lb.internal",
+				"port":         8443,
+				"secure":       true,
+				"protocol":     "http",
+				"http_path":    "/clickhouse",
+				"http_headers": map[string]interface{}{"X-Team": "data"},
+			},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				if options.Protocol != clickhouse.HTTP {
+					t.Errorf("expected http protocol, got %v", options.Protocol)
+				}
+				if options.HttpUrlPath != "/clickhouse" {
+					t.Errorf("expected http path /clickhouse, got %q", options.HttpUrlPath)
+				}
+				if options.HttpHeaders["X-Team"] != "data" {
+					t.Errorf("expected X-Team header, got %v", options.HttpHeaders)
+				}
+				if options.TLS == nil {
+					t.Errorf("expected TLS to be enabled")
+				}
+				if options.DialContext != nil {
+					t.Errorf("expected default dialer without proxy")
+				}
+			},
+		},
+		{
+			name: "http protocol with proxy",
+			config: map[string]interface{}{
+				"host":           "lb.internal",
+				"port":           8123,
+				"protocol":       "http",
+				"http_proxy_url": "http://proxy.internal:3128",
+			},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				if options.DialContext == nil {
+					t.Errorf("expected proxy dialer to be set")
+				}
+			},
+		},
+		{
+			name: "multiple endpoints",
+			config: map[string]interface{}{
+				"host": "localhost",
+				"port": 9000,
+				"endpoints": []interface{}{
+					map[string]interface{}{"host": "replica-1"},
+					map[string]interface{}{"host": "replica-2", "port": 9440},
+				},
+				"connection_open_strategy": "round_robin",
+				"dial_timeout":             5,
+			},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				expected := []string{"replica-1:9000", "replica-2:9440"}
+				if !reflect.DeepEqual(options.Addr, expected) {
+					t.Errorf("expected addresses %v, got %v", expected, options.Addr)
+				}
+				if options.ConnOpenStrategy != clickhouse.ConnOpenRoundRobin {
+					t.Errorf("expected round robin strategy, got %v", options.ConnOpenStrategy)
+				}
+				if options.DialTimeout != 5*time.Second {
+					t.Errorf("expected 5s dial timeout, got %v", options.DialTimeout)
+				}
+			},
+		},
+		{
+			name: "tls block enables TLS",
+			config: map[string]interface{}{
+				"host": "localhost",
+				"port": 9440,
+				"tls": []interface{}{
+					map[string]interface{}{"server_name": "clickhouse.internal", "insecure_skip_verify": true},
+				},
+			},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				if options.TLS == nil {
+					t.Fatalf("expected TLS to be enabled")
+				}
+				if options.TLS.ServerName != "clickhouse.internal" || !options.TLS.InsecureSkipVerify {
+					t.Errorf("expected TLS block settings to be applied, got %+v", options.TLS)
+				}
+			},
+		},
+		{
+			name: "settings",
+			config: map[string]interface{}{
+				"host": "localhost",
+				"port": 9000,
+				"settings": map[string]interface{}{
+					"alter_sync":         "2",
+					"max_execution_time": "600",
+				},
+			},
+			check: func(t *testing.T, options *clickhouse.Options) {
+				expected := clickhouse.Settings{"alter_sync": "2", "max_execution_time": "600"}
+				if !reflect.DeepEqual(options.Settings, expected) {
+					t.Errorf("expected settings %v, got %v", expected, options.Settings)
+				}
+			},
+		},
+		{
+			name: "invalid proxy url",
+			config: map[string]interface{}{
+				"host":           "lb.internal",
+				"port":           8123,
+				"protocol":       "http",
+				"http_proxy_url": "proxy.internal:3128",
+			},
+			expectError: true,
+		},
+		{
+			name: "http settings with native protocol",
+			config: map[string]interface{}{
+				"host":      "localhost",
+				"port":      9000,
+				"http_path": "/clickhouse",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, New("dev")().Schema, tt.config)
+			options, err := clickhouseOptions(d)
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, options)
+		})
+	}
+}
+
+func TestTimeoutLimits(t *testing.T) {
+	testCases := []struct {
+		name        string
+		settings    map[string]interface{}
+		expected    map[string]int
+		expectError bool
+	}{
+		{name: "default settings", expected: map[string]int{}},
+		{
+			name:     "explicit settings",
+			settings: map[string]interface{}{"max_execution_time": "1.5", "distributed_ddl_task_timeout": "0", "mutations_sync": "2"},
+			expected: map[string]int{"max_execution_time": 2, "distributed_ddl_task_timeout": 0},
+		},
+		{name: "duration", settings: map[string]interface{}{"max_execution_time": "5m"}, expectError: true},
+		{name: "negative", settings: map[string]interface{}{"distributed_ddl_task_timeout": "-1"}, expectError: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"host": "localhost", "port": 9000}
+			if tt.settings != nil {
+				config["settings"] = tt.settings
+			}
+			limits, err := timeoutLimits(schema.TestResourceDataRaw(t, New("dev")().Schema, config))
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", limits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(limits, tt.expected) {
+				t.Errorf("timeoutLimits() = %v, expected %v", limits, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfigureUnknownHost(t *testing.T) {
+	p := New("dev")()
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+	attributes := map[string]cty.Value{}
+	for name, attributeType := range block.ImpliedType().AttributeTypes() {
+		attributes[name] = cty.NullVal(attributeType)
+	}
+	attributes["host"] = cty.UnknownVal(cty.String)
+	attributes["port"] = cty.NumberIntVal(9000)
+
+	// The gRPC server passes the raw configuration along with the legacy one
+	config := terraform.NewResourceConfigShimmed(cty.ObjectVal(attributes), block)
+	config.CtyValue = cty.ObjectVal(attributes)
+
+	diags := p.Configure(context.Background(), config)
+	if diags.HasError() {
+		t.Fatalf("expected the provider to be configured, got %v", diags)
+	}
+
+	client := p.Meta().(*sdk.Client)
+	if client.ConfigError == nil || !strings.Contains(client.ConfigError.Error(), "host") {
+		t.Fatalf("expected an error mentioning the unknown host, got %v", client.ConfigError)
+	}
+	if err := client.Exec(context.Background(), "SELECT 1"); !errors.Is(err, client.ConfigError) {
+		t.Errorf("expected Exec to fail with the configuration error, got %v", err)
+	}
+	var one int
+	if err := client.QueryRow(context.Background(), "SELECT 1").Scan(&one); !errors.Is(err, client.ConfigError) {
+		t.Errorf("expected QueryRow to fail with the configuration error, got %v", err)
+	}
+}
+
+func generateCertificate(t *testing.T, commonName string) (certPEM []byte, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshalling key: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestTLSConfig(t *testing.T) {
+	caCert, _ := generateCertificate(t, "ca")
+	clientCert, clientKey := generateCertificate(t, "client")
+	_, otherKey := generateCertificate(t, "other")
+
+	dir := t.TempDir()
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+		return path
+	}
+	caFile := writeFile("ca.pem", caCert)
+	clientCertFile := writeFile("client.pem", clientCert)
+	clientKeyFile := writeFile("client.key", clientKey)
+
+	t.Run("files", func(t *testing.T) {
+		config, err := tlsConfig(map[string]interface{}{
+			"ca_cert_file":     caFile,
+			"client_cert_file": clientCertFile,
+			"client_key_file":  clientKeyFile,
+			"server_name":      "clickhouse.internal",
+			"min_version":      "1.3",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.RootCAs == nil {
+			t.Errorf("expected custom root CAs")
+		}
+		if len(config.Certificates) != 1 {
+			t.Errorf("expected a client certificate, got %d", len(config.Certificates))
+		}
+		if config.ServerName != "clickhouse.internal" {
+			t.Errorf("expected server name clickhouse.internal, got %q", config.ServerName)
+		}
+		if config.MinVersion != tls.VersionTLS13 {
+			t.Errorf("expected TLS 1.3 min version, got %x", config.MinVersion)
+		}
+	})
+
+	t.Run("pem contents", func(t *testing.T) {
+		config, err := tlsConfig(map[string]interface{}{
+			"ca_cert_pem": string(caCert),
+			"client_cert": string(clientCert),
+			"client_key":  string(clientKey),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.RootCAs == nil || len(config.Certificates) != 1 {
+			t.Errorf("expected CA bundle and client certificate to be loaded")
+		}
+		if config.MinVersion != tls.VersionTLS12 {
+			t.Errorf("expected TLS 1.2 min version by default, got %x", config.MinVersion)
+		}
+	})
+
+	errorCases := map[string]map[string]interface{}{
+		"unreadable ca file":  {"ca_cert_file": filepath.Join(dir, "missing.pem")},
+		"invalid ca bundle":   {"ca_cert_pem": "not a certificate"},
+		"unreadable key file": {"client_cert_file": clientCertFile, "client_key_file": filepath.Join(dir, "missing.key")},
+		"mismatching key":     {"client_cert": string(clientCert), "client_key": string(otherKey)},
+		"unsupported version": {"min_version": "0.9"},
+	}
+	for name, tlsMap := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tlsConfig(tlsMap); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestRetryDialStrategy(t *testing.T) {
+	dialRetryBackoff = time.Millisecond
+	options := &clickhouse.Options{Addr: []string{"replica-1:9000", "replica-2:9000"}}
+
+	var dialed []string
+	dial := func(ctx context.Context, addr string, opt *clickhouse.Options) (clickhouse.DialResult, error) {
+		dialed = append(dialed, addr)
+		// Every endpoint is down during the first pass
+		if len(dialed) <= len(options.Addr) {
+			return clickhouse.DialResult{}, fmt.Errorf("connection refused")
+		}
+		return clickhouse.DialResult{}, nil
+	}
+
+	if _, err := retryDialStrategy(1)(context.Background(), 0, options, dial); err != nil {
+		t.Fatalf("expected the second pass to succeed, got %v", err)
+	}
+	expected := []string{"replica-1:9000", "replica-2:9000", "replica-1:9000"}
+	if !reflect.DeepEqual(dialed, expected) {
+		t.Errorf("expected dial attempts %v, got %v", expected, dialed)
+	}
+
+	dialed = nil
+	failing := func(ctx context.Context, addr string, opt *clickhouse.Options) (clickhouse.DialResult, error) {
+		dialed = append(dialed, addr)
+		return clickhouse.DialResult{}, fmt.Errorf("connection refused")
+	}
+	if _, err := retryDialStrategy(2)(context.Background(), 0, options, failing); err == nil {
+		t.Fatalf("expected an error when every endpoint is down")
+	}
+	if len(dialed) != 6 {
+		t.Errorf("expected 6 dial attempts, got %d", len(dialed))
+	}
+}
+
+func TestClickhouseOptionsEnvironmentProxy(t *testing.T) {
+	defer func(lookup func(*http.Request) (*url.URL, error)) { proxyFromEnvironment = lookup }(proxyFromEnvironment)
+	var schemes []string
+	proxyFromEnvironment = func(req *http.Request) (*url.URL, error) {
+		schemes = append(schemes, req.URL.Scheme)
+		if req.URL.Host == "proxied.internal:8443" {
+			return url.Parse("http://env-proxy:3128")
+		}
+		return nil, nil
+	}
+
+	config := map[string]interface{}{
+		"host":           "clickhouse.internal",
+		"port":           8443,
+		"secure":         true,
+		"protocol":       "http",
+		"http_proxy_url": "http://proxy.internal:3128",
+	}
+	d := schema.TestResourceDataRaw(t, New("dev")().Schema, config)
+	if _, err := clickhouseOptions(d); err != nil {
+		t.Fatalf("unexpected error without a proxy in the environment: %v", err)
+	}
+	if !reflect.DeepEqual(schemes, []string{"https"}) {
+		t.Errorf("expected the https proxy to be looked up, got %v", schemes)
+	}
+
+	config["host"] = "proxied.internal"
+	d = schema.TestResourceDataRaw(t, New("dev")().Schema, config)
+	_, err := clickhouseOptions(d)
+	if err == nil || !strings.Contains(err.Error(), "env-proxy:3128") {
+		t.Fatalf("expected an error naming the proxy of the environment, got %v", err)
+	}
+
+	delete(config, "http_proxy_url")
+	d = schema.TestResourceDataRaw(t, New("dev")().Schema, config)
+	if _, err := clickhouseOptions(d); err != nil {
+		t.Fatalf("unexpected error without http_proxy_url: %v", err)
+	}
+}
+
+func TestProxyDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer listener.Close()
+
+	requests := make(chan *http.Request, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		requests <- req
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	}()
+
+	proxyURL, _ := url.Parse("http://user:secret@" + listener.Addr().String())
+	conn, err := proxyDialer(proxyURL)(context.Background(), "clickhouse.internal:8123")
+	if err != nil {
+		t.Fatalf("dialing through proxy: %v", err)
+	}
+	defer conn.Close()
+
+	req := <-requests
+	if req.Method != http.MethodConnect || req.Host != "clickhouse.internal:8123" {
+		t.Errorf("expected CONNECT clickhouse.internal:8123, got %s %s", req.Method, req.Host)
+	}
+	if req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("expected proxy credentials, got %q", req.Header.Get("Proxy-Authorization"))
+	}
+}
@@ -0,0 +1,45 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// createModeSchema returns the `create_mode` attribute for the resource type, which overrides the
+// provider `create_mode`. It only matters when the resource is created, so changing it doesn't
+// replace the resource.
+func createModeSchema(resourceType string) *schema.Schema {
+	return &schema.Schema{
+		Description:  createModeDescription(resourceType),
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice(common.CreateModes[resourceType], false),
+	}
+}
+
+// createModeAttribute is the plugin framework counterpart of createModeSchema
+func createModeAttribute(resourceType string) fwschema.StringAttribute {
+	return fwschema.StringAttribute{
+		MarkdownDescription: createModeDescription(resourceType),
+		Optional:            true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(common.CreateModes[resourceType]...),
+		},
+	}
+}
+
+func createModeDescription(resourceType string) string {
+	return fmt.Sprintf(
+		"How the %s is created, one of `%s`. `adopt` takes over an existing %s identical to the configuration and fails if it differs. Defaults to the provider `create_mode`",
+		resourceType,
+		strings.Join(common.CreateModes[resourceType], "`, `"),
+		resourceType,
+	)
+}
@@ -2,248 +2,508 @@ package resources
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"strings"
 
-	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
-
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func ResourceDb() *schema.Resource {
-	return &schema.Resource{
-		// This description is used by the documentation generator and the language server.
-		Description: "Resource to handle clickhouse databases.",
-
-		CreateContext: resourceDbCreate,
-		ReadContext:   resourceDbRead,
-		DeleteContext: resourceDbDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), ":")
-				switch len(idParts) {
-				case 2:
-					if err := d.Set("cluster", idParts[0]); err != nil {
-						return nil, err
-					}
-					if err := d.Set("name", idParts[1]); err != nil {
-						return nil, err
-					}
-				case 1:
-					if err := d.Set("name", idParts[0]); err != nil {
-						return nil, err
-					}
-				default:
-					return nil, fmt.Errorf("invalid import ID, expected <database> or <cluster>:<database>")
-				}
-
-				return []*schema.ResourceData{d}, nil
-			},
-		},
+var (
+	_ resource.ResourceWithConfigure      = &dbResource{}
+	_ resource.ResourceWithImportState    = &dbResource{}
+	_ resource.ResourceWithModifyPlan     = &dbResource{}
+	_ resource.ResourceWithUpgradeState   = &dbResource{}
+	_ resource.ResourceWithValidateConfig = &dbResource{}
+)
+
+// dbResource is served by the plugin framework provider. Its schema matches the one of the former
+// SDKv2 resource, so that existing states are read as is.
+type dbResource struct {
+	client sdk.ClickhouseClient
+}
 
-		Schema: map[string]*schema.Schema{
-			"cluster": {
-				Description: "Cluster name, not mandatory but should be provided if creating a db in a clustered server",
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Default:     "",
+type dbResourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	QuerySettings       types.Map    `tfsdk:"query_settings"`
+	CreateMode          types.String `tfsdk:"create_mode"`
+	Cluster             types.String `tfsdk:"cluster"`
+	Name                types.String `tfsdk:"name"`
+	Engine              types.Object `tfsdk:"engine"`
+	DataPath            types.String `tfsdk:"data_path"`
+	MetadataPath        types.String `tfsdk:"metadata_path"`
+	UUID                types.String `tfsdk:"uuid"`
+	Comment             types.String `tfsdk:"comment"`
+	ReplicaDrift        types.Map    `tfsdk:"replica_drift"`
+	RepairOnApply       types.Bool   `tfsdk:"repair_on_apply"`
+	ForceDestroy        types.Bool   `tfsdk:"force_destroy"`
+	ForceDestroyObjects types.List   `tfsdk:"force_destroy_objects"`
+	Timeouts            types.Object `tfsdk:"timeouts"`
+}
+
+func NewDbResource() resource.Resource {
+	return &dbResource{}
+}
+
+func (r *dbResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_db"
+}
+
+func (r *dbResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Resource to handle clickhouse databases.",
+		// Version 1 turns the engine name into the engine attributes
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"name": {
-				Description: "Database name",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+			"query_settings":        querySettingsAttribute(),
+			"create_mode":           createModeAttribute("database"),
+			"replica_drift":         replicaDriftAttribute(),
+			"repair_on_apply":       repairOnApplyAttribute("database"),
+			"force_destroy":         forceDestroyAttribute(),
+			"force_destroy_objects": forceDestroyObjectsAttribute(),
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Cluster name, not mandatory but should be provided if creating a db in a clustered server. Defaults to the provider `default_cluster`",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"engine": {
-				Description: "Database engine",
-				Type:        schema.TypeString,
-				Computed:    true,
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Database name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"data_path": {
-				Description: "Database internal path",
-				Type:        schema.TypeString,
-				Computed:    true,
+			"engine": dbEngineAttribute(),
+			"data_path": schema.StringAttribute{
+				MarkdownDescription: "Database internal path",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"metadata_path": {
-				Description: "Database internal metadata path",
-				Type:        schema.TypeString,
-				Computed:    true,
+			"metadata_path": schema.StringAttribute{
+				MarkdownDescription: "Database internal metadata path",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"uuid": {
-				Description: "Database UUID",
-				Type:        schema.TypeString,
-				Computed:    true,
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "Database UUID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"comment": {
-				Description: "Comment about the database",
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Default:     "",
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "Comment about the database, updated in place with `ALTER DATABASE ... MODIFY COMMENT` on ClickHouse >= 24.12. On older servers, changing it fails the plan",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
-func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	c := meta.(*sdk.Client)
-	var diags diag.Diagnostics
-	cluster := d.Get("cluster").(string)
+func (r *dbResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(sdk.ClickhouseClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected sdk.ClickhouseClient, got %T.", req.ProviderData))
+		return
+	}
+	r.client = client
+}
 
-	database_name := d.Get("name").(string)
-	row := c.Conn.QueryRow(ctx, fmt.Sprintf("SELECT name, engine, data_path, metadata_path, uuid, comment FROM system.databases where name = '%v'", database_name))
+func (r *dbResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ":")
+	switch len(idParts) {
+	case 2:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster"), idParts[0])...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), idParts[1])...)
+	case 1:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), idParts[0])...)
+	default:
+		resp.Diagnostics.AddError("Invalid import ID", "invalid import ID, expected <database> or <cluster>:<database>")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
 
-	if row.Err() != nil {
-		return diag.FromErr(fmt.Errorf("reading database from Clickhouse: %v", row.Err()))
+func (r *dbResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dbResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel, diags := state.statementContext(ctx, "read")
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	var name, engine, dataPath, metadataPath, uuid, comment string
+	found, diags := r.read(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// If the database doesn't exist anymore, treat this as a "new" resource that needs to be created
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	// The attributes with a default are not set by the import
+	if state.RepairOnApply.IsNull() {
+		state.RepairOnApply = types.BoolValue(false)
+	}
+	if state.ForceDestroy.IsNull() {
+		state.ForceDestroy = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(r.readReplicaDrift(ctx, &state)...)
+	resp.Diagnostics.Append(r.readForceDestroyObjects(ctx, &state)...)
 
-	err := row.Scan(&name, &engine, &dataPath, &metadataPath, &uuid, &comment)
-	if err != nil {
-		// If no rows were returned, treat this as a "new" resource that needs to be created
-		if err == sql.ErrNoRows {
-			d.SetId("")
-			return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// readReplicaDrift sets the `replica_drift` of the database read on its cluster and warns about it.
+// The replicas not being reachable only raises a warning, the previous drift is then kept.
+func (r *dbResource) readReplicaDrift(ctx context.Context, model *dbResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	drift := sdk.ReplicaDrift{}
+	if cluster := model.Cluster.ValueString(); cluster != "" {
+		var err error
+		drift, err = r.client.GetDatabaseReplicaDrift(ctx, cluster, model.Name.ValueString())
+		if err != nil {
+			diags.AddWarning(fmt.Sprintf("Unable to compare the database %s across the replicas", model.Name.ValueString()), err.Error())
+			if !model.ReplicaDrift.IsNull() && !model.ReplicaDrift.IsUnknown() {
+				return diags
+			}
+			drift = sdk.ReplicaDrift{}
 		}
-		return diag.FromErr(fmt.Errorf("scanning Clickhouse DB row: %v", err))
 	}
 
-	if name == "" {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Database %v not found", database_name),
-			Detail:   "Not possible to retrieve db from server. Could you be performing operation in a cluster? If so try configuring default cluster name on you provider configuration.",
-		})
-		return diags
+	value, d := types.MapValueFrom(ctx, types.StringType, map[string]string(drift))
+	diags.Append(d...)
+	model.ReplicaDrift = value
+	if len(drift) > 0 {
+		diags.AddWarning(replicaDriftWarning("database", model.Name.ValueString(), drift, model.RepairOnApply.ValueBool()))
 	}
+	return diags
+}
 
-	err = d.Set("name", name)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set name for db %q", name),
-		})
+// ModifyPlan checks that the server can update the comment of the database in place, and plans an
+// update of a database missing on some replicas when `repair_on_apply` is set, the repair then
+// refreshes its `replica_drift`. The destroy of a database with `force_destroy` lists the objects
+// dropped with it.
+func (r *dbResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
 	}
-	err = d.Set("engine", engine)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set engine for db %q", name),
-		})
+	var state, plan dbResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	err = d.Set("data_path", dataPath)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set data_path for db %q", name),
-		})
+	if req.Plan.Raw.IsNull() {
+		if summary, detail, ok := forceDestroyWarning(ctx, state); ok {
+			resp.Diagnostics.AddWarning(summary, detail)
+		}
+		return
 	}
-	err = d.Set("metadata_path", metadataPath)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set metadata_path for db %q", name),
-		})
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A database being replaced gets its new comment when created
+	if len(resp.RequiresReplace) == 0 && !plan.Comment.IsUnknown() && !plan.Comment.Equal(state.Comment) {
+		if err := checkFeatures(ctx, r.client, sdk.FeatureModifyDBComment); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("comment"),
+				"Unable to update the database comment",
+				fmt.Sprintf("%v. The comment of the database %s can only be changed by recreating it, e.g. with `terraform apply -replace`.", err, state.Name.ValueString()),
+			)
+			return
+		}
+	}
+
+	if plan.needsReplicaRepair(ctx, state) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("replica_drift"), types.MapUnknown(types.StringType))...)
+	}
+	if !plan.ForceDestroy.Equal(state.ForceDestroy) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("force_destroy_objects"), types.ListUnknown(types.StringType))...)
 	}
-	err = d.Set("uuid", uuid)
+}
+
+// needsReplicaRepair tells whether the database is missing on some replicas and must be repaired
+func (m *dbResourceModel) needsReplicaRepair(ctx context.Context, state dbResourceModel) bool {
+	drift := map[string]string{}
+	if !m.RepairOnApply.ValueBool() || state.ReplicaDrift.ElementsAs(ctx, &drift, false).HasError() {
+		return false
+	}
+	return len(sdk.ReplicaDrift(drift).MissingHosts()) > 0
+}
+
+// read refreshes the model from `system.databases`, reporting whether the database exists
+func (r *dbResource) read(ctx context.Context, model *dbResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	cluster := r.client.GetCluster(model.Cluster.ValueString())
+	databaseName := model.Name.ValueString()
+
+	chDatabase, err := r.client.GetDatabase(ctx, databaseName)
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set uuid for db %q", name),
-		})
+		diags.AddError("Unable to read db", err.Error())
+		return false, diags
+	}
+	if chDatabase == nil {
+		return false, diags
 	}
 
-	if err := d.Set("comment", comment); err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set comment for db %q", name),
-		})
+	if chDatabase.Name == "" {
+		diags.AddError(
+			fmt.Sprintf("Database %v not found", databaseName),
+			"Not possible to retrieve db from server. Could you be performing operation in a cluster? If so try configuring default cluster name on you provider configuration.",
+		)
+		return false, diags
 	}
 
-	err = d.Set("cluster", cluster)
+	engine, err := chDatabase.DatabaseEngine()
 	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to set cluster for db %q", name),
-		})
+		diags.AddError("Unable to read db", err.Error())
+		return false, diags
 	}
+	diags.Append(model.setDatabaseEngine(ctx, engine)...)
 
-	d.SetId(cluster + ":" + database_name)
+	model.Name = types.StringValue(chDatabase.Name)
+	model.DataPath = types.StringValue(chDatabase.DataPath)
+	model.MetadataPath = types.StringValue(chDatabase.MetadataPath)
+	model.UUID = types.StringValue(chDatabase.UUID)
+	model.Comment = types.StringValue(chDatabase.Comment)
+	model.Cluster = types.StringValue(cluster)
+	model.ID = types.StringValue(cluster + ":" + databaseName)
 
-	tflog.Trace(ctx, "DB resource created.")
+	tflog.Trace(ctx, "DB resource read.")
 
-	return diags
+	return true, diags
 }
 
-func resourceDbCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	c := meta.(*sdk.Client)
-	var diags diag.Diagnostics
-
-	cluster, _ := d.Get("cluster").(string)
-	clusterStatement := common.GetClusterStatement(cluster)
-	databaseName := d.Get("name").(string)
-	comment := d.Get("comment").(string)
-	createStatement := common.GetCreateStatement("database")
+func (r *dbResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dbResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel, diags := plan.statementContext(ctx, "create")
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	query := fmt.Sprintf("%s %v %v COMMENT '%v'", createStatement, databaseName, clusterStatement, comment)
-	err := c.Conn.Exec(ctx, query)
-	if err != nil {
-		return diag.FromErr(err)
+	database, diags := plan.databaseResource(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	database.Cluster = r.client.GetCluster(database.Cluster)
+	database.CreateMode = r.client.GetCreateMode(database.CreateMode)
+	if err := r.client.CreateDatabase(ctx, database); err != nil {
+		resp.Diagnostics.AddError("Unable to create db", err.Error())
+		return
 	}
 
-	d.SetId(cluster + ":" + databaseName)
+	plan.Cluster = types.StringValue(database.Cluster)
+	plan.ID = types.StringValue(database.Cluster + ":" + database.Name)
 
-	return diags
+	// The computed attributes are read back, they stay empty when the database can't be read yet,
+	// e.g. in dry run mode
+	found, diags := r.read(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		if database.Engine != nil {
+			resp.Diagnostics.Append(plan.setDatabaseEngine(ctx, *database.Engine)...)
+		} else {
+			plan.Engine = dbEngineFromName("")
+		}
+		plan.DataPath = types.StringValue("")
+		plan.MetadataPath = types.StringValue("")
+		plan.UUID = types.StringValue("")
+		plan.ReplicaDrift = types.MapValueMust(types.StringType, map[string]attr.Value{})
+		plan.ForceDestroyObjects = types.ListValueMust(types.StringType, nil)
+	} else {
+		resp.Diagnostics.Append(r.readReplicaDrift(ctx, &plan)...)
+		resp.Diagnostics.Append(r.readForceDestroyObjects(ctx, &plan)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceDbDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	c := meta.(*sdk.Client)
-	var diags diag.Diagnostics
+// Update alters the comment of the database, repairs it on the replicas missing it and stores the
+// new `query_settings`, `create_mode`, `repair_on_apply`, `force_destroy` and `timeouts`, every
+// other attribute forces a new database
+func (r *dbResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state dbResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel, diags := plan.statementContext(ctx, "update")
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	databaseName := d.Get("name").(string)
+	database, diags := plan.databaseResource(ctx)
+	resp.Diagnostics.Append(diags...)
+	previous, diags := state.databaseResource(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.client.UpdateDatabase(ctx, database, previous); err != nil {
+		resp.Diagnostics.AddError("Unable to update db comment", err.Error())
+		return
+	}
+
+	if plan.needsReplicaRepair(ctx, state) {
+		// The engine read back from the server recreates the database with the same arguments
+		database.CreateMode = common.CreateModeIfNotExists
+		if err := r.client.CreateDatabase(ctx, database); err != nil {
+			resp.Diagnostics.AddError("Unable to repair db on the replicas", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(r.readReplicaDrift(ctx, &plan)...)
+	}
+
+	if !plan.ForceDestroy.Equal(state.ForceDestroy) {
+		resp.Diagnostics.Append(r.readForceDestroyObjects(ctx, &plan)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dbResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state dbResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel, diags := state.statementContext(ctx, "delete")
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	databaseName := state.Name.ValueString()
 	if databaseName == "" {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Database name not found",
-			Detail:   "Not possible to destroy resource as the database name was not retrieved succesfully. Could you be performing operation in a cluster? If so try configuring default cluster name on you provider configuration.",
-		})
-		return diags
+		resp.Diagnostics.AddError(
+			"Database name not found",
+			"Not possible to destroy resource as the database name was not retrieved succesfully. Could you be performing operation in a cluster? If so try configuring default cluster name on you provider configuration.",
+		)
+		return
 	}
 
-	tables, err := c.GetDBTables(ctx, databaseName)
+	cluster := r.client.GetCluster(state.Cluster.ValueString())
+	if state.ForceDestroy.ValueBool() {
+		// The objects are read again, some may have been created since the plan
+		objects, err := r.client.GetDatabaseObjects(ctx, databaseName)
+		if err == nil {
+			err = r.client.DropDatabaseObjects(ctx, cluster, objects)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to delete db", fmt.Sprintf("resource db delete: %v", err))
+			return
+		}
+	}
 
+	tables, err := r.client.GetDBTables(ctx, databaseName)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("resource db delete: %v", err))
+		resp.Diagnostics.AddError("Unable to delete db", fmt.Sprintf("resource db delete: %v", err))
+		return
 	}
 	if len(tables) > 0 {
 		var tableNames []string
 		for _, table := range tables {
 			tableNames = append(tableNames, table.Name)
 		}
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Unable to delete db resource %q", databaseName),
-			Detail:   fmt.Sprintf("DB resource is used by another resources and is not possible to delete it. Tables: %v.", tableNames),
-		})
-		return diags
+		// In dry run mode the tables dropped in the same run still exist
+		if r.client.IsDryRun() {
+			resp.Diagnostics.AddWarning(
+				fmt.Sprintf("Database %q is not empty", databaseName),
+				fmt.Sprintf("The DROP DATABASE statement is recorded although the database still contains tables: %v.", tableNames),
+			)
+		} else {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Unable to delete db resource %q", databaseName),
+				fmt.Sprintf("DB resource is used by another resources and is not possible to delete it. Tables: %v. Set `force_destroy` and apply it to drop them with the database.", tableNames),
+			)
+			return
+		}
 	}
 
-	cluster, _ := d.Get("cluster").(string)
-	clusterStatement := common.GetClusterStatement(cluster)
+	if err := r.client.DeleteDatabase(ctx, databaseName, cluster); err != nil {
+		resp.Diagnostics.AddError("Unable to delete db", err.Error())
+	}
+}
 
-	query := fmt.Sprintf("DROP DATABASE %v %v SYNC", databaseName, clusterStatement)
+// databaseResource returns the database configured in the model
+func (m *dbResourceModel) databaseResource(ctx context.Context) (models.DatabaseResource, diag.Diagnostics) {
+	engine, diags := m.databaseEngine(ctx)
+	return models.DatabaseResource{
+		Name:       m.Name.ValueString(),
+		Cluster:    m.Cluster.ValueString(),
+		Engine:     engine,
+		Comment:    m.Comment.ValueString(),
+		CreateMode: m.CreateMode.ValueString(),
+	}, diags
+}
 
-	err = c.Conn.Exec(ctx, query)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	d.SetId("")
-	return diags
+// statementContext attaches the `query_settings`, the origin and the timeout of the operation to
+// the context used for the statements, see withQuerySettings, withOrigin and withTimeout
+func (m *dbResourceModel) statementContext(ctx context.Context, operation string) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	ctx, diags := withFrameworkQuerySettings(ctx, m.QuerySettings)
+	ctx = sdk.WithOrigin(ctx, sdk.Origin{
+		ResourceType: "clickhouse_db",
+		ResourceID:   m.ID.ValueString(),
+		ResourceName: m.Name.ValueString(),
+		Operation:    operation,
+	})
+	ctx, cancel, timeoutDiags := withFrameworkTimeout(ctx, m.Timeouts, operation)
+	diags.Append(timeoutDiags...)
+	return ctx, cancel, diags
 }
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/testutils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -16,7 +17,7 @@ func TestAccResourceDb(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() { testutils.TestAccPreCheck(t) },
 		//ProviderFactories: ProviderFactories,
-		Providers: testutils.Provider(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: dbConfig(testResourceDBDatabaseName, testResourceDBDatabaseComment),
@@ -37,7 +38,7 @@ func TestAccResourceDb(t *testing.T) {
 						"clickhouse_db.new_db", "comment", regexp.MustCompile("^"+testResourceDBDatabaseComment)),
 				),
 			},
-			// RECREATE WITH A DIFFERENT COMMENT
+			// UPDATE THE COMMENT IN PLACE
 			{
 				Config: dbConfig(testResourceDBDatabaseName2, testResourceDBDatabaseComment2),
 				Check: resource.ComposeTestCheckFunc(
@@ -76,6 +77,14 @@ func TestGetCreateStatementForDatabase(t *testing.T) {
 			},
 			ExpectedSQL: "CREATE DATABASE IF NOT EXISTS", // Replace env var has no effect
 		},
+		{
+			CreateMode:  common.CreateModeIfNotExists,
+			ExpectedSQL: "CREATE DATABASE IF NOT EXISTS",
+		},
+		{
+			CreateMode:  common.CreateModeOrReplace,
+			ExpectedSQL: "CREATE DATABASE", // Databases can't be replaced
+		},
 	}
 
 	testutils.RunGetCreateStatementTest(t, "database", testCases)
@@ -0,0 +1,336 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// dbEngineModel is the `engine` of clickhouse_db, see models.DatabaseEngine
+type dbEngineModel struct {
+	Name                    types.String `tfsdk:"name"`
+	ZookeeperPath           types.String `tfsdk:"zookeeper_path"`
+	ShardName               types.String `tfsdk:"shard_name"`
+	ReplicaName             types.String `tfsdk:"replica_name"`
+	ExpirationTimeInSeconds types.Int64  `tfsdk:"expiration_time_in_seconds"`
+	Host                    types.String `tfsdk:"host"`
+	Database                types.String `tfsdk:"database"`
+	User                    types.String `tfsdk:"user"`
+	Password                types.String `tfsdk:"password"`
+	Schema                  types.String `tfsdk:"schema"`
+	Path                    types.String `tfsdk:"path"`
+	Settings                types.Map    `tfsdk:"settings"`
+}
+
+var dbEngineAttributeTypes = map[string]attr.Type{
+	"name":                       types.StringType,
+	"zookeeper_path":             types.StringType,
+	"shard_name":                 types.StringType,
+	"replica_name":               types.StringType,
+	"expiration_time_in_seconds": types.Int64Type,
+	"host":                       types.StringType,
+	"database":                   types.StringType,
+	"user":                       types.StringType,
+	"password":                   types.StringType,
+	"schema":                     types.StringType,
+	"path":                       types.StringType,
+	"settings":                   types.MapType{ElemType: types.StringType},
+}
+
+// dbEngineAttribute is the `engine` of clickhouse_db. It is an attribute rather than a block so that
+// the engine of a database created without one is read back from the server. The arguments which
+// are not configured are read back as well, e.g. the default path of a Replicated database.
+func dbEngineAttribute() schema.SingleNestedAttribute {
+	computedString := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		}
+	}
+
+	name := computedString(fmt.Sprintf("Engine name, one of `%s`. Defaults to the default engine of the server, usually `Atomic`", strings.Join(models.DatabaseEngines, "`, `")))
+	name.Validators = []validator.String{stringvalidator.OneOf(models.DatabaseEngines...)}
+
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Database engine and its arguments, read back from `system.databases.engine_full`. Changing any of them forces a new database",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+			objectplanmodifier.RequiresReplaceIf(
+				engineRequiresReplace,
+				"Changing the engine forces a new database",
+				"Changing the engine forces a new database",
+			),
+		},
+		Attributes: map[string]schema.Attribute{
+			"name":           name,
+			"zookeeper_path": computedString("`Replicated`: path of the database in ZooKeeper, e.g. `/clickhouse/databases/{uuid}`"),
+			"shard_name":     computedString("`Replicated`: shard of the replica, e.g. `{shard}`"),
+			"replica_name":   computedString("`Replicated`: name of the replica, e.g. `{replica}`"),
+			"expiration_time_in_seconds": schema.Int64Attribute{
+				MarkdownDescription: "`Lazy`: seconds after which the tables not accessed are unloaded from memory",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"host":     computedString("`MySQL` and `PostgreSQL`: address of the server, as `host:port`"),
+			"database": computedString("`MySQL` and `PostgreSQL`: remote database"),
+			"user":     computedString("`MySQL` and `PostgreSQL`: user of the remote server"),
+			"password": schema.StringAttribute{
+				MarkdownDescription: "`MySQL` and `PostgreSQL`: password of the user, never read back from the server",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"schema": computedString("`PostgreSQL`: remote schema"),
+			"path":   computedString("`SQLite`: path of the database file"),
+			"settings": schema.MapAttribute{
+				MarkdownDescription: "Engine settings, e.g. `max_broken_tables_ratio` of a `Replicated` database",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// engineRequiresReplace replaces the database when one of the engine attributes which are planned
+// differs from the state. The ones which are not configured are unknown until read back, and the
+// password isn't known after an import.
+func engineRequiresReplace(_ context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+	stateAttributes := req.StateValue.Attributes()
+	for name, planned := range req.PlanValue.Attributes() {
+		if planned.IsUnknown() || (name == "password" && stateAttributes[name].IsNull()) {
+			continue
+		}
+		if !planned.Equal(stateAttributes[name]) {
+			resp.RequiresReplace = true
+			return
+		}
+	}
+}
+
+// databaseEngine returns the engine configured in the model, nil when it isn't configured
+func (m *dbResourceModel) databaseEngine(ctx context.Context) (*models.DatabaseEngine, diag.Diagnostics) {
+	if m.Engine.IsNull() || m.Engine.IsUnknown() {
+		return nil, nil
+	}
+	var engineModel dbEngineModel
+	diags := m.Engine.As(ctx, &engineModel, basetypes.ObjectAsOptions{})
+	if diags.HasError() || engineModel.Name.IsNull() || engineModel.Name.IsUnknown() {
+		return nil, diags
+	}
+
+	engine := models.DatabaseEngine{
+		Name:                    engineModel.Name.ValueString(),
+		ZookeeperPath:           engineModel.ZookeeperPath.ValueString(),
+		ShardName:               engineModel.ShardName.ValueString(),
+		ReplicaName:             engineModel.ReplicaName.ValueString(),
+		ExpirationTimeInSeconds: engineModel.ExpirationTimeInSeconds.ValueInt64(),
+		Host:                    engineModel.Host.ValueString(),
+		Database:                engineModel.Database.ValueString(),
+		User:                    engineModel.User.ValueString(),
+		Password:                engineModel.Password.ValueString(),
+		Schema:                  engineModel.Schema.ValueString(),
+		Path:                    engineModel.Path.ValueString(),
+	}
+	if !engineModel.Settings.IsNull() && !engineModel.Settings.IsUnknown() {
+		diags.Append(engineModel.Settings.ElementsAs(ctx, &engine.Settings, false)...)
+	}
+	return &engine, diags
+}
+
+// setDatabaseEngine sets the engine read from the server in the model. The password is kept from
+// the model, as well as empty settings so that they don't differ from the configuration.
+func (m *dbResourceModel) setDatabaseEngine(ctx context.Context, engine models.DatabaseEngine) diag.Diagnostics {
+	var diags diag.Diagnostics
+	previous := dbEngineModel{
+		Password: types.StringNull(),
+		Settings: types.MapNull(types.StringType),
+	}
+	if !m.Engine.IsNull() && !m.Engine.IsUnknown() {
+		diags.Append(m.Engine.As(ctx, &previous, basetypes.ObjectAsOptions{})...)
+	}
+
+	optionalString := func(value string) types.String {
+		if value == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(value)
+	}
+	engineModel := dbEngineModel{
+		Name:                    types.StringValue(engine.Name),
+		ZookeeperPath:           optionalString(engine.ZookeeperPath),
+		ShardName:               optionalString(engine.ShardName),
+		ReplicaName:             optionalString(engine.ReplicaName),
+		ExpirationTimeInSeconds: types.Int64Null(),
+		Host:                    optionalString(engine.Host),
+		Database:                optionalString(engine.Database),
+		User:                    optionalString(engine.User),
+		Password:                previous.Password,
+		Schema:                  optionalString(engine.Schema),
+		Path:                    optionalString(engine.Path),
+		Settings:                types.MapNull(types.StringType),
+	}
+	if previous.Password.IsUnknown() {
+		engineModel.Password = types.StringNull()
+	}
+	if engine.ExpirationTimeInSeconds != 0 {
+		engineModel.ExpirationTimeInSeconds = types.Int64Value(engine.ExpirationTimeInSeconds)
+	}
+	if len(engine.Settings) > 0 || (!previous.Settings.IsNull() && !previous.Settings.IsUnknown()) {
+		settings, d := types.MapValueFrom(ctx, types.StringType, engine.Settings)
+		diags.Append(d...)
+		engineModel.Settings = settings
+	}
+
+	value, d := types.ObjectValueFrom(ctx, dbEngineAttributeTypes, engineModel)
+	diags.Append(d...)
+	m.Engine = value
+	return diags
+}
+
+// dbEngineFromName returns the engine object of a database whose engine arguments are not known,
+// e.g. when upgrading a state storing the engine name only
+func dbEngineFromName(name string) types.Object {
+	attributes := map[string]attr.Value{}
+	for attributeName, attributeType := range dbEngineAttributeTypes {
+		switch attributeType {
+		case types.Int64Type:
+			attributes[attributeName] = types.Int64Null()
+		case types.StringType:
+			attributes[attributeName] = types.StringNull()
+		default:
+			attributes[attributeName] = types.MapNull(types.StringType)
+		}
+	}
+	if name != "" {
+		attributes["name"] = types.StringValue(name)
+	}
+	return types.ObjectValueMust(dbEngineAttributeTypes, attributes)
+}
+
+// ValidateConfig checks that the configured engine is given the arguments it accepts
+func (r *dbResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config dbResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Engine.IsNull() || config.Engine.IsUnknown() {
+		return
+	}
+	var engineModel dbEngineModel
+	resp.Diagnostics.Append(config.Engine.As(ctx, &engineModel, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if engineModel.Name.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("engine").AtName("name"), "Missing database engine name", "The name of a configured engine is required.")
+		return
+	}
+
+	engine, diags := config.databaseEngine(ctx)
+	resp.Diagnostics.Append(diags...)
+	if engine == nil {
+		return
+	}
+	// The arguments known after the plan only are checked on apply
+	for _, argument := range config.Engine.Attributes() {
+		if argument.IsUnknown() {
+			return
+		}
+	}
+	if err := engine.Validate(); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("engine"), "Invalid database engine", err.Error())
+	}
+}
+
+// dbResourceModelV0 is the clickhouse_db model of the schema version 0, storing the engine name. It
+// has the attributes added since then as well, which are null in the states of that version.
+type dbResourceModelV0 struct {
+	ID                  types.String `tfsdk:"id"`
+	QuerySettings       types.Map    `tfsdk:"query_settings"`
+	CreateMode          types.String `tfsdk:"create_mode"`
+	Cluster             types.String `tfsdk:"cluster"`
+	Name                types.String `tfsdk:"name"`
+	Engine              types.String `tfsdk:"engine"`
+	DataPath            types.String `tfsdk:"data_path"`
+	MetadataPath        types.String `tfsdk:"metadata_path"`
+	UUID                types.String `tfsdk:"uuid"`
+	Comment             types.String `tfsdk:"comment"`
+	ReplicaDrift        types.Map    `tfsdk:"replica_drift"`
+	RepairOnApply       types.Bool   `tfsdk:"repair_on_apply"`
+	ForceDestroy        types.Bool   `tfsdk:"force_destroy"`
+	ForceDestroyObjects types.List   `tfsdk:"force_destroy_objects"`
+	Timeouts            types.Object `tfsdk:"timeouts"`
+}
+
+// UpgradeState turns the engine name of the version 0, also written by the former SDKv2 resource,
+// into the engine attributes. Its arguments are read back by the next refresh.
+func (r *dbResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	priorSchema := schemaResp.Schema
+	priorSchema.Version = 0
+	priorSchema.Attributes = map[string]schema.Attribute{}
+	for name, attribute := range schemaResp.Schema.Attributes {
+		priorSchema.Attributes[name] = attribute
+	}
+	priorSchema.Attributes["engine"] = schema.StringAttribute{Computed: true}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &priorSchema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior dbResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, dbResourceModel{
+					ID:                  prior.ID,
+					QuerySettings:       prior.QuerySettings,
+					CreateMode:          prior.CreateMode,
+					Cluster:             prior.Cluster,
+					Name:                prior.Name,
+					Engine:              dbEngineFromName(prior.Engine.ValueString()),
+					DataPath:            prior.DataPath,
+					MetadataPath:        prior.MetadataPath,
+					UUID:                prior.UUID,
+					Comment:             prior.Comment,
+					ReplicaDrift:        prior.ReplicaDrift,
+					RepairOnApply:       prior.RepairOnApply,
+					ForceDestroy:        prior.ForceDestroy,
+					ForceDestroyObjects: prior.ForceDestroyObjects,
+					Timeouts:            prior.Timeouts,
+				})...)
+			},
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func forceDestroyAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Drop the dictionaries, materialized views, views and tables of the database before dropping it, in dependency order. " +
+			"It must be applied before the destroy to take effect, the objects then dropped are listed in `force_destroy_objects`",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+func forceDestroyObjectsAttribute() schema.ListAttribute {
+	return schema.ListAttribute{
+		MarkdownDescription: "Objects of the database dropped before it on destroy when `force_destroy` is set, in drop order, e.g. `materialized view analytics.events_mv`. Refreshed on read",
+		ElementType:         types.StringType,
+		Computed:            true,
+		PlanModifiers: []planmodifier.List{
+			listplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// readForceDestroyObjects sets the `force_destroy_objects` of the database, empty unless
+// `force_destroy` is set
+func (r *dbResource) readForceDestroyObjects(ctx context.Context, model *dbResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var names []attr.Value
+	if model.ForceDestroy.ValueBool() {
+		objects, err := r.client.GetDatabaseObjects(ctx, model.Name.ValueString())
+		if err != nil {
+			diags.AddError("Unable to read db objects", err.Error())
+			return diags
+		}
+		for _, object := range objects {
+			names = append(names, types.StringValue(object.String()))
+		}
+	}
+	model.ForceDestroyObjects = types.ListValueMust(types.StringType, names)
+	return diags
+}
+
+// forceDestroyWarning lists the objects dropped with the database, reported by the plan destroying it
+func forceDestroyWarning(ctx context.Context, state dbResourceModel) (string, string, bool) {
+	var objects []string
+	if !state.ForceDestroy.ValueBool() || state.ForceDestroyObjects.ElementsAs(ctx, &objects, false).HasError() || len(objects) == 0 {
+		return "", "", false
+	}
+	summary := fmt.Sprintf("The database %s will be destroyed with its %d objects", state.Name.ValueString(), len(objects))
+	detail := fmt.Sprintf("`force_destroy` drops, as of the last refresh:\n- %s", strings.Join(objects, "\n- "))
+	return summary, detail, true
+}
@@ -0,0 +1,246 @@
+package resources
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newDbResource returns the clickhouse_db resource configured with the client, and its schema
+func newDbResource(t *testing.T, c sdk.ClickhouseClient) (*dbResource, fwschema.Schema) {
+	t.Helper()
+	ctx := context.Background()
+	r := &dbResource{}
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: c}, &resource.ConfigureResponse{})
+
+	var resp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema() error = %v", resp.Diagnostics)
+	}
+	return r, resp.Schema
+}
+
+// dbValue returns a clickhouse_db object with the attributes, the other ones being null
+func dbValue(s fwschema.Schema, attributes map[string]tftypes.Value) tftypes.Value {
+	return objectValue(s.Type().TerraformType(context.Background()).(tftypes.Object), attributes)
+}
+
+// dbEngineValue returns a clickhouse_db engine with the attributes, the other ones being null
+func dbEngineValue(s fwschema.Schema, attributes map[string]tftypes.Value) tftypes.Value {
+	objectType := s.Type().TerraformType(context.Background()).(tftypes.Object)
+	return objectValue(objectType.AttributeTypes["engine"].(tftypes.Object), attributes)
+}
+
+// objectValue returns an object of the type with the attributes, the other ones being null
+func objectValue(objectType tftypes.Object, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+		} else {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	return tftypes.NewValue(objectType, values)
+}
+
+// dbAttributes are the attributes of the database analytics created on the cluster main
+func dbAttributes(s fwschema.Schema, comment string) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "main:analytics"),
+		"cluster":         tftypes.NewValue(tftypes.String, "main"),
+		"name":            tftypes.NewValue(tftypes.String, "analytics"),
+		"engine":          dbEngineValue(s, map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "Atomic")}),
+		"comment":         tftypes.NewValue(tftypes.String, comment),
+		"repair_on_apply": tftypes.NewValue(tftypes.Bool, false),
+		"replica_drift":   tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{}),
+	}
+}
+
+func TestDbResourceUpdateComment(t *testing.T) {
+	ctx := context.Background()
+	conn := sdktest.NewFakeConn().
+		AddRows(`SELECT version\(\)`, []string{"version()"}, []any{"24.12.1.1"})
+	r, s := newDbResource(t, sdk.NewClientWithConn(conn))
+
+	state := tfsdk.State{Schema: s, Raw: dbValue(s, dbAttributes(s, "Raw evnts"))}
+	plan := tfsdk.Plan{Schema: s, Raw: dbValue(s, dbAttributes(s, "Raw events"))}
+
+	planResp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: plan}, &planResp)
+	if planResp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan() error = %v", planResp.Diagnostics)
+	}
+
+	updateResp := resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: plan.Raw.Copy()}}
+	r.Update(ctx, resource.UpdateRequest{State: state, Plan: plan}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update() error = %v", updateResp.Diagnostics)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "db_update_comment.sql"), conn.Statements())
+}
+
+func TestDbResourceUpdateCommentUnsupported(t *testing.T) {
+	ctx := context.Background()
+	conn := sdktest.NewFakeConn().
+		AddRows(`SELECT version\(\)`, []string{"version()"}, []any{"23.8.2.7"})
+	r, s := newDbResource(t, sdk.NewClientWithConn(conn))
+
+	state := tfsdk.State{Schema: s, Raw: dbValue(s, dbAttributes(s, "Raw evnts"))}
+	plan := tfsdk.Plan{Schema: s, Raw: dbValue(s, dbAttributes(s, "Raw events"))}
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected the comment change to fail the plan")
+	}
+	if diagnostic := resp.Diagnostics.Errors()[0]; diagnostic.Summary() != "Unable to update the database comment" {
+		t.Errorf("ModifyPlan() error = %s: %s", diagnostic.Summary(), diagnostic.Detail())
+	}
+
+	// The comment of a database being replaced is set by its creation
+	resp = resource.ModifyPlanResponse{Plan: plan, RequiresReplace: path.Paths{path.Root("name")}}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("ModifyPlan() error = %v", resp.Diagnostics)
+	}
+}
+
+func TestDbResourceCreateEngine(t *testing.T) {
+	ctx := context.Background()
+	conn := sdktest.NewFakeConn().
+		AddRows(`FROM system.databases`,
+			[]string{"name", "engine", "engine_full", "data_path", "metadata_path", "uuid", "comment"},
+			[]any{"analytics", "Replicated", "Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')", "/var/lib/clickhouse/store/", "/var/lib/clickhouse/metadata/analytics/", "b13bbcb5-85c6-4f54-8d8f-3f8d0e1c0a47", "Raw events"},
+		)
+	r, s := newDbResource(t, sdk.NewClientWithConn(conn))
+
+	attributes := dbAttributes(s, "Raw events")
+	attributes["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	attributes["engine"] = dbEngineValue(s, map[string]tftypes.Value{
+		"name":           tftypes.NewValue(tftypes.String, "Replicated"),
+		"zookeeper_path": tftypes.NewValue(tftypes.String, "/clickhouse/databases/analytics"),
+		"shard_name":     tftypes.NewValue(tftypes.String, "{shard}"),
+		"replica_name":   tftypes.NewValue(tftypes.String, "{replica}"),
+		"settings":       tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tftypes.UnknownValue),
+	})
+	plan := tfsdk.Plan{Schema: s, Raw: dbValue(s, attributes)}
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: plan.Raw.Copy()}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create() error = %v", resp.Diagnostics)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "db_create_engine.sql"), conn.Statements())
+
+	var zookeeperPath types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("engine").AtName("zookeeper_path"), &zookeeperPath)...)
+	var settings types.Map
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("engine").AtName("settings"), &settings)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("GetAttribute() error = %v", resp.Diagnostics)
+	}
+	if zookeeperPath.ValueString() != "/clickhouse/databases/analytics" {
+		t.Errorf("engine.zookeeper_path = %s", zookeeperPath)
+	}
+	if !settings.IsNull() {
+		t.Errorf("engine.settings = %s, expected null", settings)
+	}
+}
+
+func TestDbResourceValidateEngine(t *testing.T) {
+	ctx := context.Background()
+	r, s := newDbResource(t, sdk.NewClientWithConn(sdktest.NewFakeConn()))
+
+	testCases := []struct {
+		engine        map[string]tftypes.Value
+		expectedError string
+	}{
+		{
+			engine: map[string]tftypes.Value{
+				"name":                       tftypes.NewValue(tftypes.String, "Lazy"),
+				"expiration_time_in_seconds": tftypes.NewValue(tftypes.Number, 3600),
+			},
+		},
+		{
+			engine: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "Lazy"),
+			},
+			expectedError: "Invalid database engine",
+		},
+		{
+			engine: map[string]tftypes.Value{
+				"path": tftypes.NewValue(tftypes.String, "/data/shop.db"),
+			},
+			expectedError: "Missing database engine name",
+		},
+		{
+			// The arguments known on apply only are checked then
+			engine: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "SQLite"),
+				"path": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		attributes := dbAttributes(s, "")
+		attributes["engine"] = dbEngineValue(s, tt.engine)
+		resp := resource.ValidateConfigResponse{}
+		r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: s, Raw: dbValue(s, attributes)}}, &resp)
+
+		var summary string
+		if resp.Diagnostics.HasError() {
+			summary = resp.Diagnostics.Errors()[0].Summary()
+		}
+		if summary != tt.expectedError {
+			t.Errorf("ValidateConfig(%v) error = %q, expected %q", tt.engine, summary, tt.expectedError)
+		}
+	}
+}
+
+func TestDbResourceForceDestroy(t *testing.T) {
+	ctx := context.Background()
+	conn := sdktest.NewFakeConn().
+		AddRows(`dependencies_table FROM system\.tables`,
+			[]string{"database", "name", "engine", "is_dictionary", "dependencies_database", "dependencies_table"},
+			[]any{"analytics", "events", "MergeTree", false, []string{"analytics"}, []string{"events_mv"}},
+			[]any{"analytics", "events_mv", "MaterializedView", false, []string{}, []string{}},
+		)
+	r, s := newDbResource(t, sdk.NewClientWithConn(conn))
+
+	attributes := dbAttributes(s, "")
+	attributes["force_destroy"] = tftypes.NewValue(tftypes.Bool, true)
+	attributes["force_destroy_objects"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "materialized view analytics.events_mv"),
+		tftypes.NewValue(tftypes.String, "table analytics.events"),
+	})
+	state := tfsdk.State{Schema: s, Raw: dbValue(s, attributes)}
+	destroyPlan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+
+	planResp := resource.ModifyPlanResponse{Plan: destroyPlan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: destroyPlan}, &planResp)
+	if warnings := planResp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "The database analytics will be destroyed with its 2 objects" {
+		t.Errorf("ModifyPlan() diagnostics = %v, expected the objects dropped with the database", planResp.Diagnostics)
+	}
+
+	deleteResp := resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete() error = %v", deleteResp.Diagnostics)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "db_force_destroy.sql"), conn.Statements())
+}
@@ -0,0 +1,32 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// checkFeatures fails the plan when the server doesn't support one of the features. The check is
+// skipped while the connection can't be configured, e.g. its address is not known yet: the
+// statement then fails at apply time. Any other failure to read the server version is returned.
+func checkFeatures(ctx context.Context, meta any, features ...sdk.Feature) error {
+	if client, ok := meta.(*sdk.Client); ok && client.ConfigError != nil {
+		tflog.Debug(ctx, fmt.Sprintf("skipping the server version checks: %v", client.ConfigError))
+		return nil
+	}
+
+	c := meta.(sdk.ClickhouseClient)
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, feature := range features {
+		if err := feature.Check(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
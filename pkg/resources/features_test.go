@@ -0,0 +1,35 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+func TestCheckFeatures(t *testing.T) {
+	ctx := context.Background()
+
+	supported := sdk.NewClientWithConn(sdktest.NewFakeConn().AddRows("SELECT version()", []string{"version()"}, []any{"24.3.1.1"}))
+	if err := checkFeatures(ctx, supported, sdk.FeatureModifyComment); err != nil {
+		t.Errorf("unexpected error on a supported server: %v", err)
+	}
+
+	unsupported := sdk.NewClientWithConn(sdktest.NewFakeConn().AddRows("SELECT version()", []string{"version()"}, []any{"21.3.1.1"}))
+	if err := checkFeatures(ctx, unsupported, sdk.FeatureModifyComment); err == nil {
+		t.Errorf("expected an error on an unsupported server")
+	}
+
+	unconfigured := &sdk.Client{ConfigError: errors.New("host is not known yet")}
+	if err := checkFeatures(ctx, unconfigured, sdk.FeatureModifyComment); err != nil {
+		t.Errorf("expected the checks to be skipped without a connection, got %v", err)
+	}
+
+	unreachable := sdk.NewClientWithConn(sdktest.NewFakeConn().FailOn("SELECT version()", errors.New("connection refused")))
+	if err := checkFeatures(ctx, unreachable, sdk.FeatureModifyComment); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the server version error, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withOrigin tags the statements executed for the resource operation, see sdk.Origin
+func withOrigin(ctx context.Context, d *schema.ResourceData, resourceType string, operation string) context.Context {
+	name, _ := d.Get("name").(string)
+	return sdk.WithOrigin(ctx, sdk.Origin{
+		ResourceType: resourceType,
+		ResourceID:   d.Id(),
+		ResourceName: name,
+		Operation:    operation,
+	})
+}
@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const querySettingsDescription = "Clickhouse settings attached to every statement executed for this resource, overriding the provider `settings`"
+
+func querySettingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: querySettingsDescription,
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// querySettingsAttribute is the plugin framework counterpart of querySettingsSchema
+func querySettingsAttribute() fwschema.MapAttribute {
+	return fwschema.MapAttribute{
+		MarkdownDescription: querySettingsDescription,
+		ElementType:         types.StringType,
+		Optional:            true,
+	}
+}
+
+// withQuerySettings attaches the resource `query_settings` to the context used for its statements
+func withQuerySettings(ctx context.Context, d *schema.ResourceData) context.Context {
+	settings := common.MapInterfaceToMapOfString(d.Get("query_settings").(map[string]interface{}))
+	return sdk.WithQuerySettings(ctx, settings)
+}
+
+// withFrameworkQuerySettings attaches the `query_settings` of a plugin framework resource to the
+// context used for its statements
+func withFrameworkQuerySettings(ctx context.Context, querySettings types.Map) (context.Context, diag.Diagnostics) {
+	settings := map[string]string{}
+	diags := querySettings.ElementsAs(ctx, &settings, false)
+	return sdk.WithQuerySettings(ctx, settings), diags
+}
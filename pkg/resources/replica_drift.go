@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const replicaDriftDescription = "Hosts of the `cluster` whose copy differs from the one of the connected host, with `missing` or the attributes that differ. Only read when a cluster is set"
+
+func repairOnApplyDescription(resourceType string) string {
+	return fmt.Sprintf("Re-run the `CREATE %s` statement with `IF NOT EXISTS` on the cluster when the %s is missing on some of its hosts, the other hosts are left as is. Defaults to `false`", strings.ToUpper(resourceType), resourceType)
+}
+
+// replicaDriftSchema returns the computed `replica_drift` attribute, see sdk.ReplicaDrift
+func replicaDriftSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: replicaDriftDescription,
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// repairOnApplySchema returns the `repair_on_apply` attribute for the resource type
+func repairOnApplySchema(resourceType string) *schema.Schema {
+	return &schema.Schema{
+		Description: repairOnApplyDescription(resourceType),
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// replicaDriftAttribute is the plugin framework counterpart of replicaDriftSchema
+func replicaDriftAttribute() fwschema.MapAttribute {
+	return fwschema.MapAttribute{
+		MarkdownDescription: replicaDriftDescription,
+		ElementType:         types.StringType,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Map{
+			mapplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// repairOnApplyAttribute is the plugin framework counterpart of repairOnApplySchema
+func repairOnApplyAttribute(resourceType string) fwschema.BoolAttribute {
+	return fwschema.BoolAttribute{
+		MarkdownDescription: repairOnApplyDescription(resourceType),
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+}
+
+// replicaDriftWarning describes the drift of an object for the warning reported when it is read
+func replicaDriftWarning(resourceType string, name string, drift sdk.ReplicaDrift, repairOnApply bool) (string, string) {
+	summary := fmt.Sprintf("The %s %s differs across the replicas", resourceType, name)
+	detail := drift.String()
+	switch {
+	case len(drift.MissingHosts()) == 0:
+		detail += fmt.Sprintf("\n\nThe %s must be altered on these hosts manually.", resourceType)
+	case repairOnApply:
+		detail += fmt.Sprintf("\n\nThe %s will be created on the hosts where it is missing.", resourceType)
+	default:
+		detail += fmt.Sprintf("\n\nSet `repair_on_apply` to create the %s on the hosts where it is missing.", resourceType)
+	}
+	return summary, detail
+}
+
+// readReplicaDrift sets the `replica_drift` of a table or view read on the cluster and warns
+// about it. The replicas not being reachable only raises a warning, so that the plan isn't blocked.
+func readReplicaDrift(ctx context.Context, d *schema.ResourceData, c sdk.ClickhouseClient, resourceType string, cluster string) diag.Diagnostics {
+	if cluster == "" {
+		return nil
+	}
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+
+	drift, err := c.GetTableReplicaDrift(ctx, cluster, database, name)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Unable to compare the %s %s.%s across the replicas", resourceType, database, name),
+			Detail:   err.Error(),
+		}}
+	}
+	if err := d.Set("replica_drift", map[string]string(drift)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting replica_drift: %v", err))
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	summary, detail := replicaDriftWarning(resourceType, database+"."+name, drift, d.Get("repair_on_apply").(bool))
+	return diag.Diagnostics{{Severity: diag.Warning, Summary: summary, Detail: detail}}
+}
+
+// planReplicaRepair plans an update of a resource missing on some replicas when `repair_on_apply`
+// is set, the repair then refreshes its `replica_drift`
+func planReplicaRepair(d *schema.ResourceDiff) error {
+	if d.Id() == "" || !d.Get("repair_on_apply").(bool) || !hasMissingReplicas(d.Get("replica_drift").(map[string]interface{})) {
+		return nil
+	}
+	return d.SetNewComputed("replica_drift")
+}
+
+// needsReplicaRepair tells whether an SDK resource being updated is missing on some replicas and
+// must be repaired
+func needsReplicaRepair(d *schema.ResourceData) bool {
+	old, _ := d.GetChange("replica_drift")
+	return d.Get("repair_on_apply").(bool) && hasMissingReplicas(old.(map[string]interface{}))
+}
+
+func hasMissingReplicas(drift map[string]interface{}) bool {
+	for _, value := range drift {
+		if value == sdk.DriftMissing {
+			return true
+		}
+	}
+	return false
+}
@@ -18,12 +18,21 @@ func ResourceRole() *schema.Resource {
 		ReadContext:   resourceRoleRead,
 		DeleteContext: resourceRoleDelete,
 		UpdateContext: resourceRoleUpdate,
+		Timeouts:      resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
+			"query_settings": querySettingsSchema(),
 			"name": {
 				Description: "Role name",
 				Type:        schema.TypeString,
 				Required:    true,
 			},
+			"cluster": {
+				Description: "Cluster name, the role is created on every node of the cluster. Defaults to the provider `default_cluster`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
 			"database": {
 				Description: "Database where to grant permissions to the user. You can apply privileges to all databases by using '*'",
 				Type:        schema.TypeString,
@@ -42,9 +51,13 @@ func ResourceRole() *schema.Resource {
 }
 
 func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_role", "update")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutUpdate)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	planRoleName := d.Get("name").(string)
 	planDatabase := d.Get("database").(string)
@@ -58,6 +71,7 @@ func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	role := models.RoleResource{
 		Name:       planRoleName,
+		Cluster:    d.Get("cluster").(string),
 		Database:   planDatabase,
 		Privileges: planPrivileges,
 	}
@@ -74,9 +88,13 @@ func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_role", "read")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutRead)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	roleNameState := d.Get("name").(string)
 	chRole, err := c.GetRole(ctx, roleNameState)
@@ -98,6 +116,11 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err := d.Set("privileges", &roleResource.Privileges); err != nil {
 		return diag.FromErr(fmt.Errorf("resource role read: %v", err))
 	}
+	if cluster := c.GetCluster(d.Get("cluster").(string)); cluster != "" {
+		if err := d.Set("cluster", cluster); err != nil {
+			return diag.FromErr(fmt.Errorf("resource role read: %v", err))
+		}
+	}
 
 	d.SetId(roleResource.Name)
 
@@ -105,36 +128,52 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 }
 
 func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_role", "create")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutCreate)
+	defer cancel()
 	var diags diag.Diagnostics
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	database := d.Get("database").(string)
 	roleName := d.Get("name").(string)
 	privileges := d.Get("privileges").(*schema.Set)
+	cluster := c.GetCluster(d.Get("cluster").(string))
 
 	diags = ValidatePrivileges(database, privileges)
 	if diags.HasError() {
 		return diags
 	}
 
-	chRole, err := c.CreateRole(ctx, roleName, database, common.StringSetToList(privileges))
+	chRole, err := c.CreateRole(ctx, roleName, cluster, database, common.StringSetToList(privileges))
 
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource role create: %v", err))
 	}
 
+	if cluster != "" {
+		if err := d.Set("cluster", cluster); err != nil {
+			return diag.FromErr(fmt.Errorf("resource role create: %v", err))
+		}
+	}
+
 	d.SetId(chRole.Name)
 
 	return diags
 }
 
 func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_role", "delete")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutDelete)
+	defer cancel()
 	var diags diag.Diagnostics
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	roleName := d.Get("name").(string)
+	cluster := d.Get("cluster").(string)
 
-	if err := c.DeleteRole(ctx, roleName); err != nil {
+	if err := c.DeleteRole(ctx, roleName, cluster); err != nil {
 		return diag.FromErr(fmt.Errorf("resource role delete: %v", err))
 	}
 	return diags
@@ -179,15 +179,15 @@ func TestAccResourceRole(t *testing.T) {
 	// Feature tests, user database
 	resource.Test(t, resource.TestCase{
 		//ProviderFactories: testutils.GetProviderFactories(),
-		Providers:    testutils.Provider(),
-		CheckDestroy: testAccCheckRoleResourceDestroy([]string{roleName1, roleName2}),
-		Steps:        generateRoleTestSteps(test1StepsData),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRoleResourceDestroy([]string{roleName1, roleName2}),
+		Steps:                    generateRoleTestSteps(test1StepsData),
 	})
 	// Feature tests, system database
 	resource.Test(t, resource.TestCase{
-		Providers:    testutils.Provider(),
-		CheckDestroy: testAccCheckRoleResourceDestroy([]string{roleName1, roleName2}),
-		Steps:        generateRoleTestSteps(test2StepsData),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRoleResourceDestroy([]string{roleName1, roleName2}),
+		Steps:                    generateRoleTestSteps(test2StepsData),
 	})
 
 	// This is bugged:
@@ -209,7 +209,7 @@ func TestAccResourceRole(t *testing.T) {
 	// })
 	// Validate privileges on create
 	resource.Test(t, resource.TestCase{
-		Providers: testutils.Provider(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccRoleResource(
@@ -222,7 +222,7 @@ func TestAccResourceRole(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		Providers: testutils.Provider(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccRoleResource(
@@ -236,8 +236,8 @@ func TestAccResourceRole(t *testing.T) {
 	})
 	// Validate privileges on update
 	resource.Test(t, resource.TestCase{
-		Providers:    testutils.Provider(),
-		CheckDestroy: testAccCheckRoleResourceDestroy([]string{roleName1}),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRoleResourceDestroy([]string{roleName1}),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccRoleResource(
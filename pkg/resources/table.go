@@ -20,6 +20,8 @@ func ResourceTable() *schema.Resource {
 		ReadContext:   resourceTableRead,
 		DeleteContext: resourceTableDelete,
 		UpdateContext: resourceTableUpdate,
+		CustomizeDiff: resourceTableCustomizeDiff,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ":")
@@ -48,6 +50,16 @@ func ResourceTable() *schema.Resource {
 			},
 		},
 		Schema: map[string]*schema.Schema{
+			"query_settings":  querySettingsSchema(),
+			"create_mode":     createModeSchema("table"),
+			"replica_drift":   replicaDriftSchema(),
+			"repair_on_apply": repairOnApplySchema("table"),
+			"wait_for_mutations": {
+				Description: "Wait for the mutations started by column and TTL changes (e.g. the rewrite of a column whose type changes) to be done on every replica before the update completes, within the `update` timeout. Otherwise the update completes with a warning while they run in the background. A mutation reporting a `latest_fail_reason` fails the update either way",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 			"database": {
 				Description: "DB Name where the table will bellow",
 				Type:        schema.TypeString,
@@ -66,19 +78,21 @@ func ResourceTable() *schema.Resource {
 				ForceNew:    true,
 			},
 			"cluster": {
-				Description: "Cluster Name, it is required for Replicated or Distributed tables and forbidden in other case",
+				Description: "Cluster Name, it is required for Replicated or Distributed tables and forbidden in other case. Defaults to the provider `default_cluster`",
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 			},
 			"engine": {
-				Description: "Table engine type (Supported types so far: Distributed, ReplicatedReplacingMergeTree, ReplacingMergeTree)",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:      fmt.Sprintf("Table engine type. The plan checks the engine params and the clauses supported by %s, and warns about the other engines, only checked by the server", strings.Join(models.TableEngineNames(), ", ")),
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: ValidateOnClusterEngine,
 			},
 			"engine_params": {
-				Description: "Engine params in case the engine type requires them",
+				Description: "Engine params in case the engine type requires them, e.g. the sign column of a CollapsingMergeTree. Replicated engines take the ZooKeeper path and the replica name first, both optional",
 				Type:        schema.TypeList,
 				Optional:    true,
 				ForceNew:    true,
@@ -235,11 +249,16 @@ func ResourceTable() *schema.Resource {
 }
 
 func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_table", "read")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutRead)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 	database := d.Get("database").(string)
 	tableName := d.Get("name").(string)
+	cluster := c.GetCluster(d.Get("cluster").(string))
 
 	chTable, err := c.GetTable(ctx, database, tableName)
 	if chTable == nil && err == nil {
@@ -267,8 +286,8 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) di
 	if err := d.Set("name", tableResource.Name); err != nil {
 		return diag.FromErr(fmt.Errorf("setting name: %v", err))
 	}
-	if tableResource.Cluster != "" {
-		if err := d.Set("cluster", tableResource.Cluster); err != nil {
+	if cluster != "" {
+		if err := d.Set("cluster", cluster); err != nil {
 			return diag.FromErr(fmt.Errorf("setting cluster: %v", err))
 		}
 	}
@@ -301,30 +320,20 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) di
 	}
 	// not set - settings
 
-	d.SetId(tableResource.Cluster + ":" + database + ":" + tableName)
+	d.SetId(cluster + ":" + database + ":" + tableName)
 
-	return diags
+	return append(diags, readReplicaDrift(ctx, d, c, "table", cluster)...)
 }
 
 func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_table", "create")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutCreate)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
-	tableResource := models.TableResource{}
-
-	tableResource.Cluster = d.Get("cluster").(string)
-	tableResource.Database = d.Get("database").(string)
-	tableResource.Name = d.Get("name").(string)
-	tableResource.SetColumns(d.Get("column").([]interface{}))
-	tableResource.SetIndexes(d.Get("index").([]interface{}))
-	tableResource.Engine = d.Get("engine").(string)
-	tableResource.Comment = d.Get("comment").(string)
-	tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{}))
-	tableResource.PrimaryKey = common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{}))
-	tableResource.OrderBy = common.MapArrayInterfaceToArrayOfStrings(d.Get("order_by").([]interface{}))
-	tableResource.SetPartitionBy(d.Get("partition_by").([]interface{}))
-	tableResource.Settings = common.MapInterfaceToMapOfString(d.Get("settings").(map[string]interface{}))
-	tableResource.TTL = common.MapInterfaceToMapOfString(d.Get("ttl").(map[string]interface{}))
+	c := meta.(sdk.ClickhouseClient)
+	tableResource := newTableResource(d, c)
 
 	tableResource.Validate(diags)
 	if diags.HasError() {
@@ -337,14 +346,46 @@ func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any)
 		return diag.FromErr(err)
 	}
 
+	if tableResource.Cluster != "" {
+		if err := d.Set("cluster", tableResource.Cluster); err != nil {
+			return diag.FromErr(fmt.Errorf("setting cluster: %v", err))
+		}
+	}
+
 	d.SetId(tableResource.Cluster + ":" + tableResource.Database + ":" + tableResource.Name)
 
 	return diags
 }
 
+// newTableResource reads the table to create from the resource data
+func newTableResource(d *schema.ResourceData, c sdk.ClickhouseClient) models.TableResource {
+	tableResource := models.TableResource{}
+
+	tableResource.Cluster = c.GetCluster(d.Get("cluster").(string))
+	tableResource.Database = d.Get("database").(string)
+	tableResource.Name = d.Get("name").(string)
+	tableResource.SetColumns(d.Get("column").([]interface{}))
+	tableResource.SetIndexes(d.Get("index").([]interface{}))
+	tableResource.Engine = d.Get("engine").(string)
+	tableResource.Comment = d.Get("comment").(string)
+	tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{}))
+	tableResource.PrimaryKey = common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{}))
+	tableResource.OrderBy = common.MapArrayInterfaceToArrayOfStrings(d.Get("order_by").([]interface{}))
+	tableResource.SetPartitionBy(d.Get("partition_by").([]interface{}))
+	tableResource.Settings = common.MapInterfaceToMapOfString(d.Get("settings").(map[string]interface{}))
+	tableResource.TTL = common.MapInterfaceToMapOfString(d.Get("ttl").(map[string]interface{}))
+	tableResource.CreateMode = c.GetCreateMode(d.Get("create_mode").(string))
+
+	return tableResource
+}
+
 func resourceTableDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_table", "delete")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutDelete)
+	defer cancel()
 	var diags diag.Diagnostics
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	var tableResource models.TableResource
 	tableResource.Database = d.Get("database").(string)
@@ -359,9 +400,25 @@ func resourceTableDelete(ctx context.Context, d *schema.ResourceData, meta any)
 	return diags
 }
 
+func resourceTableCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if err := validateTableEngine(d); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("comment") {
+		if err := checkFeatures(ctx, meta, sdk.FeatureModifyComment); err != nil {
+			return err
+		}
+	}
+	return planReplicaRepair(d)
+}
+
 func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_table", "update")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutUpdate)
+	defer cancel()
 	var diags diag.Diagnostics
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	tableResource := models.TableResource{}
 
@@ -372,10 +429,70 @@ func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta any)
 	tableResource.Comment = d.Get("comment").(string)
 	tableResource.TTL = common.MapInterfaceToMapOfString(d.Get("ttl").(map[string]interface{}))
 
+	// The mutations started by the update are the ones which didn't exist before it
+	cluster := c.GetCluster(tableResource.Cluster)
+	tracksMutations := !c.IsDryRun() && (d.HasChange("column") || d.HasChange("ttl"))
+	var previousMutations []models.CHMutation
+	if tracksMutations {
+		var err error
+		previousMutations, err = c.GetMutations(ctx, cluster, tableResource.Database, tableResource.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	err := c.UpdateTable(ctx, tableResource, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if tracksMutations {
+		diags = append(diags, trackTableMutations(ctx, d, c, cluster, previousMutations)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if needsReplicaRepair(d) {
+		repairResource := newTableResource(d, c)
+		repairResource.CreateMode = common.CreateModeIfNotExists
+		if err := c.CreateTable(ctx, repairResource); err != nil {
+			return diag.FromErr(fmt.Errorf("repairing the table on the replicas: %v", err))
+		}
+		diags = append(diags, readReplicaDrift(ctx, d, c, "table", repairResource.Cluster)...)
+	}
+
 	return diags
 }
+
+// trackTableMutations follows the mutations started by an update of the table, waiting for them
+// when `wait_for_mutations` is set and warning about them otherwise
+func trackTableMutations(ctx context.Context, d *schema.ResourceData, c sdk.ClickhouseClient, cluster string, previous []models.CHMutation) diag.Diagnostics {
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+
+	current, err := c.GetMutations(ctx, cluster, database, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	started := sdk.NewMutations(previous, current)
+	if len(started) == 0 {
+		return nil
+	}
+
+	if d.Get("wait_for_mutations").(bool) {
+		return diag.FromErr(c.WaitForMutations(ctx, cluster, database, name, started))
+	}
+	if err := sdk.CheckMutations(database, name, started); err != nil {
+		return diag.FromErr(err)
+	}
+	pending := sdk.PendingMutations(started)
+	if len(pending) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Mutations of the table %s.%s are running in the background", database, name),
+		Detail:   fmt.Sprintf("The mutations %s started by the update are not done yet, the table is partially migrated until they finish. Set `wait_for_mutations` to wait for them.", strings.Join(sdk.MutationIDs(pending), ", ")),
+	}}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/testutils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -17,7 +18,7 @@ func TestAccResourceTable(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() { testutils.TestAccPreCheck(t) },
 		//ProviderFactories: ProviderFactories,
-		Providers: testutils.Provider(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: tableConfigWithName(testResourceTableDatabaseName, testResourceTableTableName),
@@ -128,6 +129,21 @@ func TestGetCreateStatementForTable(t *testing.T) {
 			},
 			ExpectedSQL: "CREATE OR REPLACE TABLE", // Priority for CREATE OR REPLACE table
 		},
+		{
+			CreateMode:  common.CreateModeIfNotExists,
+			ExpectedSQL: "CREATE TABLE IF NOT EXISTS",
+		},
+		{
+			EnvVars: map[string]string{
+				"TF_VAR_CREATE_IF_NOT_EXISTS": "true",
+			},
+			CreateMode:  common.CreateModeOrReplace,
+			ExpectedSQL: "CREATE OR REPLACE TABLE", // The create mode takes precedence over env vars
+		},
+		{
+			CreateMode:  common.CreateModeAdopt,
+			ExpectedSQL: "CREATE TABLE",
+		},
 	}
 
 	testutils.RunGetCreateStatementTest(t, "TABLE", testCases)
@@ -0,0 +1,213 @@
+package resources
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// applyConfig plans and applies the configuration on the resource state, as Terraform does, and
+// returns the new state
+func applyConfig(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]any, meta any) *terraform.InstanceState {
+	t.Helper()
+	ctx := context.Background()
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	newState, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("Apply() error = %v", diags)
+	}
+	return newState
+}
+
+func tableConfig(comment string, columns ...map[string]any) map[string]any {
+	var columnList []any
+	for _, column := range columns {
+		columnList = append(columnList, column)
+	}
+	return map[string]any{
+		"database": "analytics",
+		"name":     "events",
+		"engine":   "MergeTree",
+		"comment":  comment,
+		"order_by": []any{"event_date"},
+		"column":   columnList,
+	}
+}
+
+func TestResourceTableCreateUpdateDelete(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`SELECT version\(\)`, []string{"version()"}, []any{"24.3.1.1"})
+	c := sdk.NewClientWithConn(conn)
+	c.DefaultCluster = "main"
+	r := ResourceTable()
+
+	eventDate := map[string]any{"name": "event_date", "type": "Date"}
+	eventType := map[string]any{"name": "event_type", "type": "Int32", "comment": "Type of the event"}
+
+	state := applyConfig(t, r, &terraform.InstanceState{}, tableConfig("Raw events", eventDate), c)
+	if state.ID != "main:analytics:events" {
+		t.Errorf("ID = %q, expected main:analytics:events", state.ID)
+	}
+	if state.Attributes["cluster"] != "main" {
+		t.Errorf("cluster = %q, expected main", state.Attributes["cluster"])
+	}
+
+	state = applyConfig(t, r, state, tableConfig("Events", eventDate, eventType), c)
+
+	if _, diags := r.Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, c); diags.HasError() {
+		t.Fatalf("Apply() error = %v", diags)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "table_create_update_delete.sql"), conn.Statements())
+}
+
+func TestResourceTableRead(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`FROM system\.tables`,
+			[]string{"database", "name", "engine_full", "engine", "sorting_key", "comment"},
+			[]any{"analytics", "events", "MergeTree ORDER BY event_date SETTINGS index_granularity = 8192", "MergeTree", "event_date", "Raw events"},
+		).
+		AddRows(`FROM system\.columns`,
+			[]string{"database", "table", "name", "type", "comment", "default_kind", "default_expression", "compression_codec"},
+			[]any{"analytics", "events", "event_date", "Date", "", "", "", ""},
+		)
+	r := ResourceTable()
+
+	state := &terraform.InstanceState{
+		ID:         ":analytics:events",
+		Attributes: map[string]string{"id": ":analytics:events", "database": "analytics", "name": "events"},
+	}
+	newState, diags := r.RefreshWithoutUpgrade(context.Background(), state, sdk.NewClientWithConn(conn))
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() error = %v", diags)
+	}
+
+	expected := map[string]string{
+		"engine":        "MergeTree",
+		"comment":       "Raw events",
+		"order_by.0":    "event_date",
+		"column.#":      "1",
+		"column.0.name": "event_date",
+		"column.0.type": "Date",
+	}
+	for key, value := range expected {
+		if newState.Attributes[key] != value {
+			t.Errorf("%s = %q, expected %q", key, newState.Attributes[key], value)
+		}
+	}
+
+	// The table doesn't exist anymore
+	newState, diags = r.RefreshWithoutUpgrade(context.Background(), state, sdk.NewClientWithConn(sdktest.NewFakeConn()))
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() error = %v", diags)
+	}
+	if newState != nil {
+		t.Errorf("expected the table to be removed from the state, got %v", newState)
+	}
+}
+
+func TestResourceTableReplicaDrift(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`^SELECT hostName\(\)$`, []string{"hostName()"}, []any{"ch-1"}).
+		AddRows(`clusterAllReplicas\(.+, system\.one\)`, []string{"host"}, []any{"ch-1"}, []any{"ch-2"}).
+		AddRows(`clusterAllReplicas\(.+, system\.tables\)`,
+			[]string{"host", "engine", "query", "comment"},
+			[]any{"ch-1", "MergeTree ORDER BY event_date", "", "Raw events"},
+		).
+		AddRows(`clusterAllReplicas\(.+, system\.columns\)`, []string{"host", "columns"}, []any{"ch-1", "event_date Date"}).
+		AddRows(`FROM system\.tables`,
+			[]string{"database", "name", "engine_full", "engine", "sorting_key", "comment"},
+			[]any{"analytics", "events", "MergeTree ORDER BY event_date", "MergeTree", "event_date", "Raw events"},
+		).
+		AddRows(`FROM system\.columns`,
+			[]string{"database", "table", "name", "type", "comment", "default_kind", "default_expression", "compression_codec"},
+			[]any{"analytics", "events", "event_date", "Date", "", "", "", ""},
+		)
+	c := sdk.NewClientWithConn(conn)
+	r := ResourceTable()
+
+	state := &terraform.InstanceState{
+		ID:         "main:analytics:events",
+		Attributes: map[string]string{"id": "main:analytics:events", "database": "analytics", "name": "events", "cluster": "main", "repair_on_apply": "true"},
+	}
+	state, diags := r.RefreshWithoutUpgrade(context.Background(), state, c)
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() error = %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a drift warning, got %v", diags)
+	}
+	if state.Attributes["replica_drift.ch-2"] != sdk.DriftMissing {
+		t.Errorf("replica_drift = %v, expected ch-2 to be missing", state.Attributes)
+	}
+
+	config := tableConfig("Raw events", map[string]any{"name": "event_date", "type": "Date"})
+	config["cluster"] = "main"
+	config["repair_on_apply"] = true
+	applyConfig(t, r, state, config, c)
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "table_replica_repair.sql"), conn.Statements())
+}
+
+func TestResourceTableUpdateMutations(t *testing.T) {
+	mutationColumns := []string{"host", "mutation_id", "command", "is_done", "latest_fail_reason"}
+	previous := []any{"ch-1", "mutation_1.txt", "DELETE WHERE event_type = 0", true, ""}
+	started := []any{"ch-1", "mutation_2.txt", "MODIFY COLUMN `event_type` Int64", false, ""}
+	done := []any{"ch-1", "mutation_2.txt", "MODIFY COLUMN `event_type` Int64", true, ""}
+
+	eventDate := map[string]any{"name": "event_date", "type": "Date"}
+	eventType := map[string]any{"name": "event_type", "type": "Int32"}
+	r := ResourceTable()
+	state := applyConfig(t, r, &terraform.InstanceState{}, tableConfig("Raw events", eventDate, eventType), sdk.NewClientWithConn(sdktest.NewFakeConn()))
+
+	eventType = map[string]any{"name": "event_type", "type": "Int64"}
+	config := tableConfig("Raw events", eventDate, eventType)
+	ctx := context.Background()
+
+	t.Run("warning", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRowsOnce(`system\.mutations`, mutationColumns, previous).
+			AddRows(`system\.mutations`, mutationColumns, previous, started)
+		c := sdk.NewClientWithConn(conn)
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), c)
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		_, diags := r.Apply(ctx, state, diff, c)
+		if diags.HasError() || len(diags) != 1 || !strings.Contains(diags[0].Detail, "mutation_2.txt") {
+			t.Errorf("expected a warning about mutation_2.txt, got %v", diags)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRowsOnce(`system\.mutations`, mutationColumns, previous).
+			AddRowsOnce(`system\.mutations`, mutationColumns, previous, started).
+			AddRows(`system\.mutations`, mutationColumns, previous, done)
+		c := sdk.NewClientWithConn(conn)
+
+		config["wait_for_mutations"] = true
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), c)
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		if _, diags := r.Apply(ctx, state, diff, c); len(diags) != 0 {
+			t.Errorf("Apply() diagnostics = %v, expected none", diags)
+		}
+		if queries := conn.Queries(); len(queries) != 3 {
+			t.Errorf("expected system.mutations to be read 3 times, got %v", queries)
+		}
+	})
+}
@@ -2,29 +2,51 @@ package resources
 
 import (
 	"fmt"
+	"strings"
 
-	v "github.com/go-playground/validator/v10"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 	hashicorpcty "github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// ValidateOnClusterEngine warns when the table engine is missing from the registry, see
+// models.TableEngines: its params and clauses can't be checked at plan time
 func ValidateOnClusterEngine(inValue any, p hashicorpcty.Path) diag.Diagnostics {
-	validate := v.New()
-	value := inValue.(string)
-	mergeTreeTypes := "ReplacingMergeTree"
-	replicatedTypes := "ReplicatedMergeTree"
-	replicatedReplacingTypes := "ReplicatedReplacingMergeTree"
-	distributedTypes := "Distributed"
-	kafkaTypes := "Kafka"
-	validation := fmt.Sprintf("oneof=%v %v %v %v %v", replicatedTypes, distributedTypes, mergeTreeTypes, replicatedReplacingTypes, kafkaTypes)
 	var diags diag.Diagnostics
-	if validate.Var(value, validation) != nil {
-		diag := diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "wrong value",
-			Detail:   fmt.Sprintf("%q is not %q %q %q %q %q", value, replicatedTypes, distributedTypes, mergeTreeTypes, replicatedReplacingTypes, kafkaTypes),
-		}
-		diags = append(diags, diag)
+	value := inValue.(string)
+	if _, ok := models.TableEngines[value]; !ok {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "unknown table engine",
+			Detail:        fmt.Sprintf("%q is not one of %s, its engine params and clauses are only checked by the server", value, strings.Join(models.TableEngineNames(), ", ")),
+			AttributePath: p,
+		})
 	}
 	return diags
 }
+
+// tableEngineAttributes are the attributes of clickhouse_table checked against its engine
+var tableEngineAttributes = []string{"engine", "engine_params", "order_by", "partition_by", "primary_key", "ttl", "settings"}
+
+// validateTableEngine checks the planned table against what its engine accepts, once the attributes
+// checked are known
+func validateTableEngine(d *schema.ResourceDiff) error {
+	for _, name := range tableEngineAttributes {
+		if !d.NewValueKnown(name) {
+			return nil
+		}
+	}
+
+	tableResource := models.TableResource{
+		Engine:       d.Get("engine").(string),
+		EngineParams: common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{})),
+		PrimaryKey:   common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{})),
+		OrderBy:      common.MapArrayInterfaceToArrayOfStrings(d.Get("order_by").([]interface{})),
+		Settings:     common.MapInterfaceToMapOfString(d.Get("settings").(map[string]interface{})),
+		TTL:          common.MapInterfaceToMapOfString(d.Get("ttl").(map[string]interface{})),
+	}
+	tableResource.SetPartitionBy(d.Get("partition_by").([]interface{}))
+	return tableResource.ValidateEngine()
+}
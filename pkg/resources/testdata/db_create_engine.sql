@@ -0,0 +1 @@
+CREATE DATABASE `analytics` ON CLUSTER `main` ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') COMMENT 'Raw events';
@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS `analytics`.`events_mv` ON CLUSTER `main` SYNC;
+DROP TABLE IF EXISTS `analytics`.`events` ON CLUSTER `main` SYNC;
+DROP DATABASE `analytics` ON CLUSTER `main` SYNC;
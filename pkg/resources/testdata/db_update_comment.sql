@@ -0,0 +1 @@
+ALTER DATABASE `analytics` ON CLUSTER `main` MODIFY COMMENT 'Raw events';
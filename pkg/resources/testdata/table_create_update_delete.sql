@@ -0,0 +1,6 @@
+CREATE TABLE `analytics`.`events` ON CLUSTER `main` (
+	`event_date` Date
+) ENGINE = MergeTree() ORDER BY (event_date) COMMENT 'Raw events';
+ALTER TABLE `analytics`.`events` ON CLUSTER `main` MODIFY COMMENT 'Events';
+ALTER TABLE `analytics`.`events` ON CLUSTER `main` ADD COLUMN `event_type` Int32 COMMENT 'Type of the event' AFTER `event_date`;
+DROP TABLE IF EXISTS `analytics`.`events` ON CLUSTER `main`;
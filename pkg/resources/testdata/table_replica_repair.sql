@@ -0,0 +1,3 @@
+CREATE TABLE IF NOT EXISTS `analytics`.`events` ON CLUSTER `main` (
+	`event_date` Date
+) ENGINE = MergeTree() ORDER BY (event_date) COMMENT 'Raw events';
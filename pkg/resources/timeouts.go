@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// defaultTimeout bounds the create, update and delete operations
+	defaultTimeout = 20 * time.Minute
+	// defaultReadTimeout bounds the read operations, like the former `max_execution_time` default
+	defaultReadTimeout = 5 * time.Minute
+)
+
+// resourceTimeouts returns the timeouts of an SDK resource, every operation can be configured in
+// its `timeouts` block
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultTimeout),
+		Read:   schema.DefaultTimeout(defaultReadTimeout),
+		Update: schema.DefaultTimeout(defaultTimeout),
+		Delete: schema.DefaultTimeout(defaultTimeout),
+	}
+}
+
+// withTimeout bounds the statements of an SDK resource operation by its timeout, see sdk.WithTimeout
+func withTimeout(ctx context.Context, d *schema.ResourceData, operation string) (context.Context, context.CancelFunc) {
+	return sdk.WithTimeout(ctx, d.Timeout(operation))
+}
+
+// durationRegexp matches the durations accepted by time.ParseDuration, e.g. `30s` or `1h30m`
+var durationRegexp = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`)
+
+var timeoutsAttributeTypes = map[string]attr.Type{
+	schema.TimeoutCreate: types.StringType,
+	schema.TimeoutRead:   types.StringType,
+	schema.TimeoutUpdate: types.StringType,
+	schema.TimeoutDelete: types.StringType,
+}
+
+// timeoutsBlock is the plugin framework counterpart of resourceTimeouts
+func timeoutsBlock() fwschema.SingleNestedBlock {
+	attributes := map[string]fwschema.Attribute{}
+	for operation := range timeoutsAttributeTypes {
+		defaultValue := defaultTimeout
+		if operation == schema.TimeoutRead {
+			defaultValue = defaultReadTimeout
+		}
+		attributes[operation] = fwschema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Timeout of the %s operation, e.g. `30s` or `1h`. Defaults to `%s`", operation, defaultValue),
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(durationRegexp, "must be a duration, e.g. `30s` or `1h`"),
+			},
+		}
+	}
+	return fwschema.SingleNestedBlock{Attributes: attributes}
+}
+
+// withFrameworkTimeout bounds the statements of a plugin framework resource operation by the
+// timeout configured in its `timeouts` block, see sdk.WithTimeout
+func withFrameworkTimeout(ctx context.Context, timeouts types.Object, operation string) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	timeout := defaultTimeout
+	if operation == schema.TimeoutRead {
+		timeout = defaultReadTimeout
+	}
+
+	if !timeouts.IsNull() && !timeouts.IsUnknown() {
+		if value, ok := timeouts.Attributes()[operation].(types.String); ok && !value.IsNull() && !value.IsUnknown() {
+			parsed, err := time.ParseDuration(value.ValueString())
+			if err != nil {
+				diags.AddError(fmt.Sprintf("Invalid %s timeout", operation), err.Error())
+			} else {
+				timeout = parsed
+			}
+		}
+	}
+
+	ctx, cancel := sdk.WithTimeout(ctx, timeout)
+	return ctx, cancel, diags
+}
@@ -0,0 +1,47 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithFrameworkTimeout(t *testing.T) {
+	timeouts := types.ObjectValueMust(timeoutsAttributeTypes, map[string]attr.Value{
+		schema.TimeoutCreate: types.StringValue("1h"),
+		schema.TimeoutRead:   types.StringNull(),
+		schema.TimeoutUpdate: types.StringValue("soon"),
+		schema.TimeoutDelete: types.StringNull(),
+	})
+
+	testCases := []struct {
+		name      string
+		timeouts  types.Object
+		operation string
+		expected  time.Duration
+		invalid   bool
+	}{
+		{name: "configured", timeouts: timeouts, operation: schema.TimeoutCreate, expected: time.Hour},
+		{name: "read default", timeouts: timeouts, operation: schema.TimeoutRead, expected: defaultReadTimeout},
+		{name: "no block", timeouts: types.ObjectNull(timeoutsAttributeTypes), operation: schema.TimeoutDelete, expected: defaultTimeout},
+		{name: "invalid", timeouts: timeouts, operation: schema.TimeoutUpdate, expected: defaultTimeout, invalid: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel, diags := withFrameworkTimeout(context.Background(), tt.timeouts, tt.operation)
+			defer cancel()
+			if diags.HasError() != tt.invalid {
+				t.Errorf("withFrameworkTimeout() diagnostics = %v", diags)
+			}
+			deadline, ok := ctx.Deadline()
+			if left := time.Until(deadline); !ok || left > tt.expected || left < tt.expected-time.Minute {
+				t.Errorf("deadline in %v, expected %v", left, tt.expected)
+			}
+		})
+	}
+}
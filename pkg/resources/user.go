@@ -15,14 +15,23 @@ func ResourceUser() *schema.Resource {
 		Description:   "Resource to manage Clickhouse users",
 		CreateContext: resourceUserCreate,
 		UpdateContext: resourceUserUpdate,
+		Timeouts:      resourceTimeouts(),
 		ReadContext:   resourceUserRead,
 		DeleteContext: resourceUserDelete,
 		Schema: map[string]*schema.Schema{
+			"query_settings": querySettingsSchema(),
 			"name": {
 				Description: "User name",
 				Type:        schema.TypeString,
 				Required:    true,
 			},
+			"cluster": {
+				Description: "Cluster name, the user is created on every node of the cluster. Defaults to the provider `default_cluster`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
 			"password": {
 				Description: "User password",
 				Type:        schema.TypeString,
@@ -41,9 +50,13 @@ func ResourceUser() *schema.Resource {
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_user", "read")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutRead)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	userName := d.Get("name").(string)
 	user, err := c.GetUser(ctx, userName)
@@ -57,21 +70,31 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err := d.Set("roles", &user.Roles); err != nil {
 		return diag.FromErr(err)
 	}
+	if cluster := c.GetCluster(d.Get("cluster").(string)); cluster != "" {
+		if err := d.Set("cluster", cluster); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	d.SetId(user.Name)
 
 	return diags
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_user", "create")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutCreate)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	userName := d.Get("name").(string)
 	password := d.Get("password").(string)
 	rolesSet := d.Get("roles").(*schema.Set)
 	user := models.UserResource{
 		Name:     userName,
+		Cluster:  c.GetCluster(d.Get("cluster").(string)),
 		Password: password,
 		Roles:    rolesSet,
 	}
@@ -80,15 +103,25 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.FromErr(fmt.Errorf("resource user create: %v", err))
 	}
 
+	if user.Cluster != "" {
+		if err := d.Set("cluster", user.Cluster); err != nil {
+			return diag.FromErr(fmt.Errorf("resource user create: %v", err))
+		}
+	}
+
 	d.SetId(chUser.Name)
 
 	return diags
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_user", "update")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutUpdate)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	planUserName := d.Get("name").(string)
 	planPassword := d.Get("password").(string)
@@ -97,6 +130,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	// After modify original role grants, we need to update default roles
 	user := models.UserResource{
 		Name:     planUserName,
+		Cluster:  d.Get("cluster").(string),
 		Password: planPassword,
 		Roles:    planRoles,
 	}
@@ -112,13 +146,18 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_user", "delete")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutDelete)
+	defer cancel()
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	userName := d.Get("name").(string)
+	cluster := d.Get("cluster").(string)
 
-	err := c.DeleteUser(ctx, userName)
+	err := c.DeleteUser(ctx, userName, cluster)
 
 	if err != nil {
 		return diag.FromErr(err)
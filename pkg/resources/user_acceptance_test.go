@@ -108,9 +108,9 @@ func generateUserTestSteps() []resource.TestStep {
 func TestAccResourceUserRole(t *testing.T) {
 	// Feature tests
 	resource.Test(t, resource.TestCase{
-		Providers:    testutils.Provider(),
-		CheckDestroy: testAccCheckUserResourceDestroy([]string{userName1, userName2}),
-		Steps:        generateUserTestSteps(),
+		ProtoV6ProviderFactories: testutils.ProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserResourceDestroy([]string{userName1, userName2}),
+		Steps:                    generateUserTestSteps(),
 	})
 }
 
@@ -19,8 +19,15 @@ func ResourceView() *schema.Resource {
 
 		CreateContext: resourceViewCreate,
 		ReadContext:   resourceViewRead,
+		UpdateContext: resourceViewUpdate,
+		Timeouts:      resourceTimeouts(),
 		DeleteContext: resourceViewDelete,
+		CustomizeDiff: resourceViewCustomizeDiff,
 		Schema: map[string]*schema.Schema{
+			"query_settings":  querySettingsSchema(),
+			"create_mode":     createModeSchema("view"),
+			"replica_drift":   replicaDriftSchema(),
+			"repair_on_apply": repairOnApplySchema("view"),
 			"database": {
 				Description: "DB Name where the view will bellow",
 				Type:        schema.TypeString,
@@ -40,7 +47,7 @@ func ResourceView() *schema.Resource {
 				ForceNew:    true,
 			},
 			"cluster": {
-				Description: "Cluster Name",
+				Description: "Cluster Name. Defaults to the provider `default_cluster`",
 				Type:        schema.TypeString,
 				Optional:    true,
 				ForceNew:    true,
@@ -73,6 +80,10 @@ func ResourceView() *schema.Resource {
 }
 
 func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_view", "read")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutRead)
+	defer cancel()
 	writer := bufio.NewWriter(os.Stdout)
 
 	defer func() {
@@ -83,9 +94,10 @@ func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 
 	var diags diag.Diagnostics
 
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 	database := d.Get("database").(string)
 	viewName := d.Get("name").(string)
+	cluster := c.GetCluster(d.Get("cluster").(string))
 
 	chView, err := c.GetView(ctx, database, viewName)
 
@@ -110,8 +122,8 @@ func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 		return diag.FromErr(fmt.Errorf("setting name: %v", err))
 	}
 
-	if viewResource.Cluster != "" {
-		if err := d.Set("cluster", viewResource.Cluster); err != nil {
+	if cluster != "" {
+		if err := d.Set("cluster", cluster); err != nil {
 			return diag.FromErr(fmt.Errorf("setting cluster: %v", err))
 		}
 	}
@@ -127,22 +139,18 @@ func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 		}
 	}
 
-	d.SetId(viewResource.Cluster + ":" + database + ":" + viewName)
+	d.SetId(cluster + ":" + database + ":" + viewName)
 
-	return diags
+	return append(diags, readReplicaDrift(ctx, d, c, "view", cluster)...)
 }
 
 func resourceViewCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	c := meta.(*sdk.Client)
-	viewResource := models.ViewResource{}
-
-	viewResource.Cluster = d.Get("cluster").(string)
-	viewResource.Database = d.Get("database").(string)
-	viewResource.Name = d.Get("name").(string)
-	viewResource.Query = d.Get("query").(string)
-	viewResource.Materialized = d.Get("materialized").(bool)
-	viewResource.ToTable = d.Get("to_table").(string)
-	viewResource.Comment = d.Get("comment").(string)
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_view", "create")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutCreate)
+	defer cancel()
+	c := meta.(sdk.ClickhouseClient)
+	viewResource := newViewResource(d, c)
 
 	diags := viewResource.Validate()
 	if diags.HasError() {
@@ -155,14 +163,67 @@ func resourceViewCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.FromErr(err)
 	}
 
+	if viewResource.Cluster != "" {
+		if err := d.Set("cluster", viewResource.Cluster); err != nil {
+			return diag.FromErr(fmt.Errorf("setting cluster: %v", err))
+		}
+	}
+
 	d.SetId(viewResource.Cluster + ":" + viewResource.Database + ":" + viewResource.Name)
 
 	return diags
 }
 
+// newViewResource reads the view to create from the resource data
+func newViewResource(d *schema.ResourceData, c sdk.ClickhouseClient) models.ViewResource {
+	viewResource := models.ViewResource{}
+
+	viewResource.Cluster = c.GetCluster(d.Get("cluster").(string))
+	viewResource.Database = d.Get("database").(string)
+	viewResource.Name = d.Get("name").(string)
+	viewResource.Query = d.Get("query").(string)
+	viewResource.Materialized = d.Get("materialized").(bool)
+	viewResource.ToTable = d.Get("to_table").(string)
+	viewResource.Comment = d.Get("comment").(string)
+	viewResource.CreateMode = c.GetCreateMode(d.Get("create_mode").(string))
+
+	return viewResource
+}
+
+func resourceViewCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("materialized").(bool) && d.Get("create_mode").(string) == common.CreateModeOrReplace {
+		return fmt.Errorf("materialized views can't be created with the %q create mode", common.CreateModeOrReplace)
+	}
+	return planReplicaRepair(d)
+}
+
+// resourceViewUpdate stores the new `query_settings`, `create_mode` and `repair_on_apply`, and
+// repairs the view on the replicas missing it, every other attribute forces a new view
+func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	if !needsReplicaRepair(d) {
+		return nil
+	}
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_view", "update")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutUpdate)
+	defer cancel()
+	c := meta.(sdk.ClickhouseClient)
+
+	viewResource := newViewResource(d, c)
+	viewResource.CreateMode = common.CreateModeIfNotExists
+	if err := c.CreateView(ctx, viewResource); err != nil {
+		return diag.FromErr(fmt.Errorf("repairing the view on the replicas: %v", err))
+	}
+	return readReplicaDrift(ctx, d, c, "view", viewResource.Cluster)
+}
+
 func resourceViewDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = withQuerySettings(ctx, d)
+	ctx = withOrigin(ctx, d, "clickhouse_view", "delete")
+	ctx, cancel := withTimeout(ctx, d, schema.TimeoutDelete)
+	defer cancel()
 	var diags diag.Diagnostics
-	c := meta.(*sdk.Client)
+	c := meta.(sdk.ClickhouseClient)
 
 	var viewResource models.ViewResource
 	viewResource.Database = d.Get("database").(string)
@@ -0,0 +1,133 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeError   = "error"
+	AuditOutcomeDryRun  = "dry_run"
+)
+
+// Origin identifies the resource operation a statement is executed for. It is sent with every
+// statement as the `log_comment` setting, so that `system.query_log` entries can be traced back
+// to Terraform.
+type Origin struct {
+	ResourceType    string `json:"resource_type"`
+	ResourceID      string `json:"resource_id,omitempty"`
+	ResourceName    string `json:"resource_name,omitempty"`
+	Operation       string `json:"operation"`
+	ProviderVersion string `json:"provider_version,omitempty"`
+}
+
+type originKey struct{}
+
+// WithOrigin returns a copy of the context tagging the statements executed with it
+func WithOrigin(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// origin returns the origin attached to the context, completed with the provider version
+func (c *Client) origin(ctx context.Context) (Origin, bool) {
+	origin, ok := ctx.Value(originKey{}).(Origin)
+	if ok {
+		origin.ProviderVersion = c.ProviderVersion
+	}
+	return origin, ok
+}
+
+// logComment is the `log_comment` setting value: the origin of the statement and, for an ON CLUSTER
+// statement followed in `system.distributed_ddl_queue`, the ID telling its entries apart
+type logComment struct {
+	*Origin
+	DistributedDDLID string `json:"distributed_ddl_id,omitempty"`
+}
+
+func (l logComment) String() string {
+	comment, _ := json.Marshal(l)
+	return string(comment)
+}
+
+// queryID derives a deterministic query_id, formatted as an UUID, from the origin, the statement
+// and its parameters: the same change applied twice gets the same ID. Only statements modifying
+// Clickhouse get one, as the server rejects a query_id already used by a running query.
+func queryID(origin Origin, query string, parameters map[string]string) string {
+	fields := []string{origin.ResourceType, origin.ResourceID, origin.ResourceName, origin.Operation, query}
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, name, parameters[name])
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	// Name based UUID version and variant bits
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// auditEntry is a line of the audit log file
+type auditEntry struct {
+	Time       string   `json:"time"`
+	QueryID    string   `json:"query_id,omitempty"`
+	Statement  string   `json:"statement"`
+	DurationMs int64    `json:"duration_ms"`
+	Endpoints  []string `json:"endpoints,omitempty"`
+	Outcome    string   `json:"outcome"`
+	Error      string   `json:"error,omitempty"`
+	Origin     *Origin  `json:"origin,omitempty"`
+}
+
+// audit appends the outcome of a write statement to `AuditLogFile`. Failing to write the audit
+// log doesn't fail the statement, which has already been executed.
+func (c *Client) audit(ctx context.Context, query string, start time.Time, outcome string, err error) {
+	if c.AuditLogFile == "" {
+		return
+	}
+
+	entry := auditEntry{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Statement:  strings.TrimSpace(query),
+		DurationMs: time.Since(start).Milliseconds(),
+		Outcome:    outcome,
+	}
+	// The driver doesn't tell which of the configured addresses executed the statement
+	if c.Options != nil {
+		entry.Endpoints = c.Options.Addr
+	}
+	if origin, ok := c.origin(ctx); ok {
+		parameters, _ := ctx.Value(queryParametersKey{}).(map[string]string)
+		entry.QueryID = queryID(origin, query, parameters)
+		entry.Origin = &origin
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	line, _ := json.Marshal(entry)
+
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+
+	file, err := os.OpenFile(c.AuditLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("opening audit_log_file: %v", err))
+		return
+	}
+	defer file.Close()
+	if _, err := fmt.Fprintf(file, "%s\n", line); err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("writing audit_log_file: %v", err))
+	}
+}
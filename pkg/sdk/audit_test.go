@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestQueryID(t *testing.T) {
+	origin := Origin{ResourceType: "clickhouse_table", ResourceID: ":db:t", ResourceName: "t", Operation: "update"}
+
+	id := queryID(origin, "ALTER TABLE db.t MODIFY COMMENT 'c'", nil)
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("expected an UUID, got %q", id)
+	}
+	if other := queryID(origin, "ALTER TABLE db.t MODIFY COMMENT 'c'", nil); other != id {
+		t.Errorf("expected the same query_id for the same statement, got %q and %q", id, other)
+	}
+	if other := queryID(origin, "ALTER TABLE db.t MODIFY COMMENT {comment:String}", map[string]string{"comment": "c"}); other == queryID(origin, "ALTER TABLE db.t MODIFY COMMENT {comment:String}", map[string]string{"comment": "d"}) {
+		t.Errorf("expected a different query_id for other parameters, got %q", other)
+	}
+	origin.Operation = "create"
+	if other := queryID(origin, "ALTER TABLE db.t MODIFY COMMENT 'c'", nil); other == id {
+		t.Errorf("expected a different query_id for another operation, got %q", other)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	auditLogFile := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := &Client{DryRun: true, AuditLogFile: auditLogFile, ProviderVersion: "1.2.3", Options: &clickhouse.Options{Addr: []string{"ch-1:9000", "ch-2:9000"}}}
+	ctx := WithOrigin(context.Background(), Origin{ResourceType: "clickhouse_role", ResourceName: "reader", Operation: "delete"})
+
+	if err := client.DeleteRole(ctx, "reader", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(auditLogFile)
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one audit entry, got %d", len(lines))
+	}
+
+	var entry auditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("parsing audit entry: %v", err)
+	}
+	if entry.Statement != "DROP ROLE `reader`" || entry.Outcome != AuditOutcomeDryRun {
+		t.Errorf("unexpected audit entry %+v", entry)
+	}
+	if !reflect.DeepEqual(entry.Endpoints, []string{"ch-1:9000", "ch-2:9000"}) {
+		t.Errorf("unexpected audit entry endpoints %v", entry.Endpoints)
+	}
+	if entry.Origin == nil || entry.Origin.ProviderVersion != "1.2.3" || entry.Origin.Operation != "delete" {
+		t.Errorf("unexpected audit entry origin %+v", entry.Origin)
+	}
+	if entry.QueryID != queryID(Origin{ResourceType: "clickhouse_role", ResourceName: "reader", Operation: "delete", ProviderVersion: "1.2.3"}, "DROP ROLE `reader`", nil) {
+		t.Errorf("unexpected audit entry query_id %q", entry.QueryID)
+	}
+}
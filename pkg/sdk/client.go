@@ -1,9 +1,282 @@
 package sdk
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 )
 
+// Client executes statements on Clickhouse. The connection is only opened on first use, so that
+// the provider can be configured with values which are not known yet while planning, e.g. the
+// host of a server created in the same run.
 type Client struct {
-	Conn driver.Conn
+	// Options are the options used to open the connection
+	Options *clickhouse.Options
+	// ConfigError, when set, is returned by every statement instead of opening the connection,
+	// e.g. when the provider configuration is not known yet
+	ConfigError    error
+	DefaultCluster string
+	// CreateMode is the default create mode of the databases, tables and views, see common.CreateModes
+	CreateMode string
+	// MaxRetries is the number of additional attempts for idempotent statements failing with a transient error
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled after every attempt
+	RetryBackoff time.Duration
+	// DryRun records the write statements to SQLOutputFile instead of executing them, reads still hit the server
+	DryRun        bool
+	SQLOutputFile string
+	// AuditLogFile, when set, receives a JSON line for every write statement
+	AuditLogFile string
+	// DistributedDDLWaitTimeout, when set, is how long the hosts which didn't finish an ON CLUSTER
+	// statement are followed in `system.distributed_ddl_queue`, counted from the statement start
+	DistributedDDLWaitTimeout time.Duration
+	// ProviderVersion is sent along with the origin of the statements
+	ProviderVersion string
+	// TimeoutLimits are the TimeoutSettings explicitly set by the provider settings, in seconds:
+	// the timeout of an operation can't raise them. 0, i.e. unlimited, doesn't limit it.
+	TimeoutLimits map[string]int
+
+	mu   sync.Mutex
+	conn driver.Conn
+
+	versionMu     sync.Mutex
+	serverVersion *ServerVersion
+
+	outputMu sync.Mutex
+	auditMu  sync.Mutex
+}
+
+type querySettingsKey struct{}
+
+// WithQuerySettings returns a copy of the context carrying Clickhouse settings that the client
+// attaches to every statement executed with it, on top of the provider level settings
+func WithQuerySettings(ctx context.Context, settings map[string]string) context.Context {
+	if len(settings) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, querySettingsKey{}, settings)
+}
+
+type queryParametersKey struct{}
+
+// WithParameters returns a copy of the context carrying server side query parameters, referenced
+// as `{name:String}` in the statements executed with it, so that the values are never part of the SQL
+func WithParameters(ctx context.Context, parameters map[string]string) context.Context {
+	return context.WithValue(ctx, queryParametersKey{}, parameters)
+}
+
+type timeoutKey struct{}
+
+// TimeoutSettings are the settings bounding the statements executed with a context carrying a timeout
+var TimeoutSettings = []string{"max_execution_time", "distributed_ddl_task_timeout"}
+
+// WithTimeout bounds the statements executed with the returned context by the timeout of an
+// operation: the context gets a deadline, and the statements get the time left before it as
+// `max_execution_time` and `distributed_ddl_task_timeout`, unless WithQuerySettings sets them or
+// TimeoutLimits are lower
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, timeoutKey{}, timeout), cancel
+}
+
+// NewClientWithConn returns a client running its statements on an opened connection instead of
+// opening one from Options, e.g. a fake connection in tests
+func NewClientWithConn(conn driver.Conn) *Client {
+	return &Client{conn: conn}
+}
+
+// GetCluster returns the given cluster, or the provider default cluster when none is provided
+func (c *Client) GetCluster(cluster string) string {
+	if cluster != "" {
+		return cluster
+	}
+	return c.DefaultCluster
+}
+
+// GetCreateMode returns the given create mode, or the provider create mode when none is provided
+func (c *Client) GetCreateMode(createMode string) string {
+	if createMode != "" {
+		return createMode
+	}
+	return c.CreateMode
+}
+
+// IsDryRun reports whether the write statements are only recorded, see DryRun
+func (c *Client) IsDryRun() bool {
+	return c.DryRun
+}
+
+// connection returns the Clickhouse connection, opening it on first use. A failed attempt isn't
+// cached, the next statement tries to connect again.
+func (c *Client) connection(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	if c.ConfigError != nil {
+		return nil, c.ConfigError
+	}
+
+	conn, err := clickhouse.Open(c.Options)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to clickhouse: %v", err)
+	}
+	if err := conn.Ping(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ping clickhouse database: %w", err)
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+// Exec executes a statement with the settings attached to the context, retrying it on transient
+// errors when it is idempotent. In dry run mode the statement is only recorded.
+func (c *Client) Exec(ctx context.Context, query string, args ...any) error {
+	start := time.Now()
+	if c.DryRun {
+		err := c.recordStatement(ctx, query)
+		c.audit(ctx, query, start, AuditOutcomeDryRun, err)
+		return err
+	}
+
+	conn, err := c.connection(ctx)
+	if err == nil {
+		err = c.withRetry(ctx, query, isIdempotentStatement(query), func() error {
+			if isOnClusterStatement(query) {
+				return c.execOnCluster(ctx, conn, query, args...)
+			}
+			return conn.Exec(c.queryContext(ctx, query, true), query, args...)
+		})
+	}
+
+	outcome := AuditOutcomeSuccess
+	if err != nil {
+		outcome = AuditOutcomeError
+	}
+	c.audit(ctx, query, start, outcome, err)
+	return err
+}
+
+// Query runs a query with the settings attached to the context, retrying it on transient errors
+func (c *Client) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	conn, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows driver.Rows
+	err = c.withRetry(ctx, query, true, func() error {
+		var err error
+		rows, err = conn.Query(c.queryContext(ctx, query, false), query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow runs a single row query with the settings attached to the context, retrying it on transient errors
+func (c *Client) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	conn, err := c.connection(ctx)
+	if err != nil {
+		return errorRow{err: err}
+	}
+	var row driver.Row
+	_ = c.withRetry(ctx, query, true, func() error {
+		row = conn.QueryRow(c.queryContext(ctx, query, false), query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// errorRow is the row returned by QueryRow when the connection can't be opened
+type errorRow struct {
+	err error
+}
+
+func (r errorRow) Err() error           { return r.err }
+func (r errorRow) Scan(...any) error    { return r.err }
+func (r errorRow) ScanStruct(any) error { return r.err }
+
+// queryContext attaches the settings of querySettings and the parameters stored with WithParameters
+// to the query options, and tags a write statement with a query_id derived from the origin stored
+// with WithOrigin
+func (c *Client) queryContext(ctx context.Context, query string, write bool) context.Context {
+	var options []clickhouse.QueryOption
+	chSettings := c.querySettings(ctx, query)
+	parameters, _ := ctx.Value(queryParametersKey{}).(map[string]string)
+	if origin, ok := c.origin(ctx); ok && write {
+		options = append(options, clickhouse.WithQueryID(queryID(origin, query, parameters)))
+	}
+	if len(chSettings) > 0 {
+		options = append(options, clickhouse.WithSettings(chSettings))
+	}
+	if len(parameters) > 0 {
+		options = append(options, clickhouse.WithParameters(clickhouse.Parameters(parameters)))
+	}
+
+	if len(options) == 0 {
+		return ctx
+	}
+	return clickhouse.Context(ctx, options...)
+}
+
+// querySettings returns the settings of a statement on top of the provider level ones: the ones
+// stored with WithQuerySettings, the timeout stored with WithTimeout, the distributed DDL output
+// mode, and the origin stored with WithOrigin along with the distributed DDL ID as `log_comment`
+func (c *Client) querySettings(ctx context.Context, query string) clickhouse.Settings {
+	chSettings := clickhouse.Settings{}
+	if settings, ok := ctx.Value(querySettingsKey{}).(map[string]string); ok {
+		for key, value := range settings {
+			chSettings[key] = value
+		}
+	}
+	if _, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		if deadline, ok := ctx.Deadline(); ok {
+			seconds := int(math.Ceil(time.Until(deadline).Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			for _, name := range TimeoutSettings {
+				if _, ok := chSettings[name]; ok {
+					continue
+				}
+				// The explicit provider settings stay the upper bound, the timeout only shortens them
+				if limit := c.TimeoutLimits[name]; limit > 0 && limit <= seconds {
+					continue
+				}
+				chSettings[name] = seconds
+			}
+		}
+	}
+	if isOnClusterStatement(query) && !c.hasSetting(chSettings, "distributed_ddl_output_mode") {
+		chSettings["distributed_ddl_output_mode"] = distributedDDLOutputMode
+	}
+	if _, ok := chSettings["log_comment"]; !ok {
+		var comment logComment
+		if origin, ok := c.origin(ctx); ok {
+			comment.Origin = &origin
+		}
+		comment.DistributedDDLID, _ = ctx.Value(distributedDDLIDKey{}).(string)
+		if comment.Origin != nil || comment.DistributedDDLID != "" {
+			chSettings["log_comment"] = comment.String()
+		}
+	}
+	return chSettings
+}
+
+// hasSetting tells whether the setting is set for the statement or by the provider `settings`
+func (c *Client) hasSetting(settings clickhouse.Settings, name string) bool {
+	if _, ok := settings[name]; ok {
+		return true
+	}
+	if c.Options == nil {
+		return false
+	}
+	_, ok := c.Options.Settings[name]
+	return ok
 }
@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestQuerySettings(t *testing.T) {
+	// The default provider settings
+	c := &Client{Options: &clickhouse.Options{Settings: clickhouse.Settings{"max_execution_time": 300}}}
+
+	settings := c.querySettings(context.Background(), "SELECT 1")
+	if len(settings) != 0 {
+		t.Errorf("querySettings() = %v, expected the provider settings only", settings)
+	}
+
+	// A timeout longer than the default max_execution_time replaces it
+	ctx, cancel := WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	settings = c.querySettings(ctx, "DROP TABLE `analytics`.`events` ON CLUSTER `main` SYNC")
+	for _, name := range []string{"max_execution_time", "distributed_ddl_task_timeout"} {
+		if seconds, ok := settings[name].(int); !ok || seconds < 3590 || seconds > 3600 {
+			t.Errorf("%s = %v, expected the hour left before the deadline", name, settings[name])
+		}
+	}
+	if settings["distributed_ddl_output_mode"] != distributedDDLOutputMode {
+		t.Errorf("distributed_ddl_output_mode = %v, expected %s", settings["distributed_ddl_output_mode"], distributedDDLOutputMode)
+	}
+
+	// The resource query settings win over its timeout
+	settings = c.querySettings(WithQuerySettings(ctx, map[string]string{"max_execution_time": "60"}), "SELECT 1")
+	if settings["max_execution_time"] != "60" {
+		t.Errorf("max_execution_time = %v, expected the query setting", settings["max_execution_time"])
+	}
+	if _, ok := settings["distributed_ddl_task_timeout"]; !ok {
+		t.Error("expected distributed_ddl_task_timeout to be set from the timeout")
+	}
+
+	// The provider settings set explicitly stay the upper bound, unless unlimited
+	c.TimeoutLimits = map[string]int{"max_execution_time": 300, "distributed_ddl_task_timeout": 0}
+	settings = c.querySettings(ctx, "SELECT 1")
+	if _, ok := settings["max_execution_time"]; ok {
+		t.Errorf("max_execution_time = %v, expected the provider setting", settings["max_execution_time"])
+	}
+	if seconds, ok := settings["distributed_ddl_task_timeout"].(int); !ok || seconds < 3590 || seconds > 3600 {
+		t.Errorf("distributed_ddl_task_timeout = %v, expected the hour left before the deadline", settings["distributed_ddl_task_timeout"])
+	}
+
+	// A timeout shorter than the provider settings bounds the statements
+	shortCtx, cancelShort := WithTimeout(context.Background(), time.Minute)
+	defer cancelShort()
+	settings = c.querySettings(shortCtx, "SELECT 1")
+	for _, name := range []string{"max_execution_time", "distributed_ddl_task_timeout"} {
+		if seconds, ok := settings[name].(int); !ok || seconds < 50 || seconds > 60 {
+			t.Errorf("%s = %v, expected the minute left before the deadline", name, settings[name])
+		}
+	}
+}
+
+// queryParameters returns the parameters of a query, stored with WithParameters in its context
+func queryParameters(ctx context.Context) map[string]string {
+	parameters, _ := ctx.Value(queryParametersKey{}).(map[string]string)
+	return parameters
+}
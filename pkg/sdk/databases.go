@@ -2,18 +2,158 @@ package sdk
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 )
 
+// GetDatabase reads a database from `system.databases`, nil when it doesn't exist
+func (c *Client) GetDatabase(ctx context.Context, name string) (*models.CHDatabase, error) {
+	row := c.QueryRow(
+		WithParameters(ctx, map[string]string{"name": name}),
+		"SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases WHERE name = {name:String}",
+	)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading database from Clickhouse: %v", row.Err())
+	}
+
+	var chDatabase models.CHDatabase
+	err := row.ScanStruct(&chDatabase)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse database row: %v", err)
+	}
+	return &chDatabase, nil
+}
+
+// GetDatabases reads the databases selected by the filter from `system.databases`, ordered by name
+// and paged by the filter limit and offset
+func (c *Client) GetDatabases(ctx context.Context, filter models.DatabaseFilter) ([]models.CHDatabase, error) {
+	parameters := map[string]string{}
+	var conditions []string
+	if filter.NameRegex != "" {
+		parameters["name_regex"] = filter.NameRegex
+		conditions = append(conditions, "match(name, {name_regex:String})")
+	}
+	if len(filter.Engines) > 0 {
+		var engines []string
+		for i, engine := range filter.Engines {
+			name := fmt.Sprintf("engine_%d", i)
+			parameters[name] = engine
+			engines = append(engines, fmt.Sprintf("{%s:String}", name))
+		}
+		conditions = append(conditions, fmt.Sprintf("engine IN (%s)", strings.Join(engines, ", ")))
+	}
+	if filter.ExcludeSystem {
+		var systemDatabases []string
+		for _, name := range models.SystemDatabases {
+			systemDatabases = append(systemDatabases, common.QuoteString(name))
+		}
+		conditions = append(conditions, fmt.Sprintf("name NOT IN (%s)", strings.Join(systemDatabases, ", ")))
+	}
+	if filter.CommentContains != "" {
+		parameters["comment_contains"] = filter.CommentContains
+		conditions = append(conditions, "position(comment, {comment_contains:String}) > 0")
+	}
+
+	query := "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY name"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
+	rows, err := c.Query(WithParameters(ctx, parameters), query)
+	if err != nil {
+		return nil, fmt.Errorf("reading databases from Clickhouse: %v", err)
+	}
+	defer rows.Close()
+
+	databases := []models.CHDatabase{}
+	for rows.Next() {
+		var chDatabase models.CHDatabase
+		if err := rows.ScanStruct(&chDatabase); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse database row: %v", err)
+		}
+		databases = append(databases, chDatabase)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading databases from Clickhouse: %v", err)
+	}
+	return databases, nil
+}
+
+// buildCreateDatabaseSentence returns the CREATE DATABASE statement of the create mode, with the
+// engine when one is configured
+func buildCreateDatabaseSentence(database models.DatabaseResource) string {
+	statement := common.NewStatement(common.GetCreateStatement("database", database.CreateMode)).
+		Identifier(database.Name).
+		OnCluster(database.Cluster)
+	if database.Engine != nil {
+		statement.Raw("ENGINE =", database.Engine.SQL())
+	}
+	return statement.Comment(database.Comment).SQL()
+}
+
+// CreateDatabase creates the database, or adopts an identical existing one in the adopt mode
+func (c *Client) CreateDatabase(ctx context.Context, database models.DatabaseResource) error {
+	database.Cluster = c.GetCluster(database.Cluster)
+	if database.CreateMode == common.CreateModeAdopt {
+		existing, err := c.GetDatabase(ctx, database.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			existingResource, err := existing.ToResource()
+			if err != nil {
+				return err
+			}
+			if differences := database.Differences(existingResource); len(differences) > 0 {
+				return fmt.Errorf("database %s already exists and differs from the configuration: %s", database.Name, strings.Join(differences, ", "))
+			}
+			return nil
+		}
+	}
+	return c.Exec(ctx, buildCreateDatabaseSentence(database))
+}
+
+// UpdateDatabase alters the comment of the database when it differs from the previous one, the
+// other attributes can't be altered
+func (c *Client) UpdateDatabase(ctx context.Context, database models.DatabaseResource, previous models.DatabaseResource) error {
+	if database.Comment == previous.Comment {
+		return nil
+	}
+	query := common.NewStatement("ALTER DATABASE").
+		Identifier(database.Name).
+		OnCluster(c.GetCluster(database.Cluster)).
+		Raw("MODIFY COMMENT").
+		Literal(database.Comment).
+		SQL()
+	return c.Exec(ctx, query)
+}
+
+func (c *Client) DeleteDatabase(ctx context.Context, name string, cluster string) error {
+	return c.Exec(ctx, common.NewStatement("DROP DATABASE").Identifier(name).OnCluster(c.GetCluster(cluster)).Raw("SYNC").SQL())
+}
+
 func (c *Client) GetDBTables(ctx context.Context, database string) ([]models.CHTable, error) {
-	query := fmt.Sprintf("SELECT database, name FROM system.tables where database = '%s'", database)
-	rows, err := c.Conn.Query(ctx, query)
+	ctx = WithParameters(ctx, map[string]string{"database": database})
+	rows, err := c.Query(ctx, "SELECT database, name FROM system.tables WHERE database = {database:String}")
 
 	if err != nil {
 		return nil, fmt.Errorf("reading tables from Clickhouse: %v", err)
 	}
+	defer rows.Close()
 
 	var tables []models.CHTable
 	for rows.Next() {
@@ -24,6 +164,107 @@ func (c *Client) GetDBTables(ctx context.Context, database string) ([]models.CHT
 		}
 		tables = append(tables, table)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading tables from Clickhouse: %v", err)
+	}
 
 	return tables, nil
 }
+
+// GetDatabaseObjects reads the dictionaries, views and tables of a database in the order they can be
+// dropped, see DropOrder
+func (c *Client) GetDatabaseObjects(ctx context.Context, database string) ([]models.CHDatabaseObject, error) {
+	ctx = WithParameters(ctx, map[string]string{"database": database})
+	rows, err := c.Query(ctx, "SELECT database, name, engine, CAST(engine = 'Dictionary' AND name IN (SELECT name FROM system.dictionaries WHERE database = {database:String}) AS Bool) AS is_dictionary, dependencies_database, dependencies_table FROM system.tables WHERE database = {database:String} ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("reading database objects from Clickhouse: %v", err)
+	}
+	defer rows.Close()
+
+	var objects []models.CHDatabaseObject
+	for rows.Next() {
+		var object models.CHDatabaseObject
+		if err := rows.ScanStruct(&object); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse database object row: %v", err)
+		}
+		objects = append(objects, object)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading database objects from Clickhouse: %v", err)
+	}
+	return DropOrder(objects), nil
+}
+
+// databaseObjectKinds lists the kinds of database objects in the order they are dropped
+var databaseObjectKinds = []string{
+	models.DatabaseObjectDictionary,
+	models.DatabaseObjectMaterializedView,
+	models.DatabaseObjectView,
+	models.DatabaseObjectTable,
+}
+
+// DropOrder sorts the objects of a database so that each one is dropped after the objects of the
+// same database depending on it, and otherwise dictionaries first, then materialized views, views
+// and tables. The objects of a dependency cycle keep that order.
+func DropOrder(objects []models.CHDatabaseObject) []models.CHDatabaseObject {
+	rank := map[string]int{}
+	for i, kind := range databaseObjectKinds {
+		rank[kind] = i
+	}
+	remaining := append([]models.CHDatabaseObject{}, objects...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return rank[remaining[i].Kind()] < rank[remaining[j].Kind()]
+	})
+
+	var ordered []models.CHDatabaseObject
+	for len(remaining) > 0 {
+		next := 0
+		for i, object := range remaining {
+			if !hasRemainingDependencies(object, remaining) {
+				next = i
+				break
+			}
+		}
+		ordered = append(ordered, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return ordered
+}
+
+// hasRemainingDependencies tells whether an object of the same database still to be dropped
+// depends on the object
+func hasRemainingDependencies(object models.CHDatabaseObject, remaining []models.CHDatabaseObject) bool {
+	for i, table := range object.DependenciesTable {
+		if i >= len(object.DependenciesDatabase) || object.DependenciesDatabase[i] != object.Database || table == object.Name {
+			continue
+		}
+		for _, other := range remaining {
+			if other.Name == table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DropDatabaseObjects drops the objects of a database in the given order, see GetDatabaseObjects
+func (c *Client) DropDatabaseObjects(ctx context.Context, cluster string, objects []models.CHDatabaseObject) error {
+	for _, object := range objects {
+		statement := "DROP TABLE IF EXISTS"
+		switch object.Kind() {
+		case models.DatabaseObjectDictionary:
+			statement = "DROP DICTIONARY IF EXISTS"
+		case models.DatabaseObjectMaterializedView, models.DatabaseObjectView:
+			statement = "DROP VIEW IF EXISTS"
+		}
+		query := common.NewStatement(statement).
+			QualifiedName(object.Database, object.Name).
+			OnCluster(cluster).
+			Raw("SYNC").
+			SQL()
+		if err := c.Exec(ctx, query); err != nil {
+			return fmt.Errorf("dropping %s: %v", object, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+// existingDatabase registers the database analytics with the comment
+func existingDatabase(conn *sdktest.FakeConn, engineFull string, comment string) *sdktest.FakeConn {
+	engine, _, _ := strings.Cut(engineFull, "(")
+	return conn.AddRows(`FROM system\.databases`,
+		[]string{"name", "engine", "engine_full", "data_path", "metadata_path", "uuid", "comment"},
+		[]any{"analytics", engine, engineFull, "/var/lib/clickhouse/store/", "/var/lib/clickhouse/metadata/analytics/", "b13bbcb5-85c6-4f54-8d8f-3f8d0e1c0a47", comment},
+	)
+}
+
+func TestGetDatabase(t *testing.T) {
+	conn := existingDatabase(sdktest.NewFakeConn(), "Lazy(3600)", "Raw events")
+	database, err := NewClientWithConn(conn).GetDatabase(context.Background(), "analytics")
+	if err != nil {
+		t.Fatalf("GetDatabase() error = %v", err)
+	}
+	resource, err := database.ToResource()
+	if err != nil {
+		t.Fatalf("ToResource() error = %v", err)
+	}
+	expected := &models.DatabaseResource{
+		Name:    "analytics",
+		Engine:  &models.DatabaseEngine{Name: models.DatabaseEngineLazy, ExpirationTimeInSeconds: 3600},
+		Comment: "Raw events",
+	}
+	if !reflect.DeepEqual(resource, expected) {
+		t.Errorf("ToResource() = %+v, expected %+v", resource, expected)
+	}
+
+	database, err = NewClientWithConn(sdktest.NewFakeConn()).GetDatabase(context.Background(), "analytics")
+	if database != nil || err != nil {
+		t.Errorf("GetDatabase() = %v, %v, expected no database", database, err)
+	}
+}
+
+func TestDatabaseCreateUpdateDelete(t *testing.T) {
+	conn := sdktest.NewFakeConn()
+	client := NewClientWithConn(conn)
+	client.DefaultCluster = "main"
+	ctx := context.Background()
+
+	database := models.DatabaseResource{
+		Name:    "analytics",
+		Engine:  &models.DatabaseEngine{Name: models.DatabaseEngineReplicated},
+		Comment: "Raw events",
+	}
+	if err := client.CreateDatabase(ctx, database); err != nil {
+		t.Fatalf("CreateDatabase() error = %v", err)
+	}
+	updated := database
+	updated.Comment = "Events"
+	if err := client.UpdateDatabase(ctx, updated, database); err != nil {
+		t.Fatalf("UpdateDatabase() error = %v", err)
+	}
+	if err := client.UpdateDatabase(ctx, updated, updated); err != nil {
+		t.Fatalf("UpdateDatabase() error = %v", err)
+	}
+	if err := client.DeleteDatabase(ctx, "analytics", ""); err != nil {
+		t.Fatalf("DeleteDatabase() error = %v", err)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "database_create_update_delete.sql"), conn.Statements())
+}
+
+func TestCreateDatabaseAdopt(t *testing.T) {
+	database := models.DatabaseResource{
+		Name:       "analytics",
+		Engine:     &models.DatabaseEngine{Name: models.DatabaseEngineAtomic},
+		Comment:    "Raw events",
+		CreateMode: common.CreateModeAdopt,
+	}
+
+	conn := existingDatabase(sdktest.NewFakeConn(), "Atomic", "Raw events")
+	if err := NewClientWithConn(conn).CreateDatabase(context.Background(), database); err != nil {
+		t.Fatalf("CreateDatabase() error = %v", err)
+	}
+	if statements := conn.Statements(); len(statements) != 0 {
+		t.Errorf("expected the database to be adopted, got %v", statements)
+	}
+
+	conn = existingDatabase(sdktest.NewFakeConn(), "Lazy(3600)", "Raw events")
+	err := NewClientWithConn(conn).CreateDatabase(context.Background(), database)
+	if err == nil || !strings.Contains(err.Error(), "engine is Lazy(3600) instead of Atomic") {
+		t.Errorf("expected the database engine to differ, got %v", err)
+	}
+}
+
+func TestGetDatabaseObjects(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`FROM system\.tables`,
+			[]string{"database", "name", "engine", "is_dictionary", "dependencies_database", "dependencies_table"},
+			[]any{"analytics", "daily", "View", false, []string{}, []string{}},
+			[]any{"analytics", "events", "MergeTree", false, []string{"analytics", "reports"}, []string{"events_mv", "events_mv"}},
+			[]any{"analytics", "events_mv", "MaterializedView", false, []string{"analytics"}, []string{"z_mv"}},
+			[]any{"analytics", "totals", "SummingMergeTree", false, []string{}, []string{}},
+			[]any{"analytics", "users", "MergeTree", false, []string{}, []string{}},
+			[]any{"analytics", "users_dict", "Dictionary", true, []string{}, []string{}},
+			[]any{"analytics", "z_mv", "MaterializedView", false, []string{}, []string{}},
+		)
+	client := NewClientWithConn(conn)
+
+	objects, err := client.GetDatabaseObjects(context.Background(), "analytics")
+	if err != nil {
+		t.Fatalf("GetDatabaseObjects() error = %v", err)
+	}
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.String())
+	}
+	// z_mv reads from events_mv, the materialized view of events in the other database is ignored
+	expected := []string{
+		"dictionary analytics.users_dict",
+		"materialized view analytics.z_mv",
+		"materialized view analytics.events_mv",
+		"view analytics.daily",
+		"table analytics.events",
+		"table analytics.totals",
+		"table analytics.users",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("GetDatabaseObjects() = %v, expected %v", names, expected)
+	}
+	if open := conn.OpenRows(); open != 0 {
+		t.Errorf("expected the rows to be closed, %d are still open", open)
+	}
+
+	if err := client.DropDatabaseObjects(context.Background(), "main", objects); err != nil {
+		t.Fatalf("DropDatabaseObjects() error = %v", err)
+	}
+	sdktest.AssertGolden(t, filepath.Join("testdata", "drop_database_objects.sql"), conn.Statements())
+}
+
+func TestGetDBTables(t *testing.T) {
+	conn := sdktest.NewFakeConn().AddRows(`FROM system\.tables`, []string{"database", "name"},
+		[]any{"analytics", "events"},
+		[]any{"analytics", "users"},
+	)
+
+	tables, err := NewClientWithConn(conn).GetDBTables(context.Background(), "analytics")
+	if err != nil {
+		t.Fatalf("GetDBTables() error = %v", err)
+	}
+	if len(tables) != 2 || tables[0].Name != "events" || tables[1].Name != "users" {
+		t.Errorf("GetDBTables() = %v", tables)
+	}
+	if parameters := queryParameters(conn.Contexts()[0]); parameters["database"] != "analytics" {
+		t.Errorf("expected the database to be bound as a parameter, got %v", parameters)
+	}
+	if open := conn.OpenRows(); open != 0 {
+		t.Errorf("expected the rows to be closed, %d are still open", open)
+	}
+}
+
+func TestGetDatabases(t *testing.T) {
+	testCases := []struct {
+		filter             models.DatabaseFilter
+		expectedQuery      string
+		expectedParameters map[string]string
+	}{
+		{
+			expectedQuery: "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases ORDER BY name",
+		},
+		{
+			filter: models.DatabaseFilter{
+				NameRegex:       "^analytics_",
+				Engines:         []string{"Atomic", "Replicated"},
+				ExcludeSystem:   true,
+				CommentContains: "it's",
+			},
+			expectedQuery: "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases " +
+				"WHERE match(name, {name_regex:String}) AND engine IN ({engine_0:String}, {engine_1:String}) " +
+				"AND name NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema') " +
+				"AND position(comment, {comment_contains:String}) > 0 ORDER BY name",
+			expectedParameters: map[string]string{
+				"name_regex":       "^analytics_",
+				"engine_0":         "Atomic",
+				"engine_1":         "Replicated",
+				"comment_contains": "it's",
+			},
+		},
+		{
+			filter:        models.DatabaseFilter{Limit: 10},
+			expectedQuery: "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases ORDER BY name LIMIT 10",
+		},
+		{
+			filter: models.DatabaseFilter{ExcludeSystem: true, Limit: 10, Offset: 20},
+			expectedQuery: "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases " +
+				"WHERE name NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema') ORDER BY name LIMIT 10 OFFSET 20",
+		},
+		{
+			filter:        models.DatabaseFilter{Offset: 20},
+			expectedQuery: "SELECT name, engine, engine_full, data_path, metadata_path, uuid, comment FROM system.databases ORDER BY name OFFSET 20",
+		},
+	}
+
+	for _, tt := range testCases {
+		conn := existingDatabase(sdktest.NewFakeConn(), "Atomic", "Raw events")
+		databases, err := NewClientWithConn(conn).GetDatabases(context.Background(), tt.filter)
+		if err != nil {
+			t.Fatalf("GetDatabases() error = %v", err)
+		}
+		if len(databases) != 1 || databases[0].Name != "analytics" {
+			t.Errorf("GetDatabases() = %v, expected analytics", databases)
+		}
+		if queries := conn.Queries(); len(queries) != 1 || queries[0] != tt.expectedQuery {
+			t.Fatalf("GetDatabases() queries = %q, expected %q", queries, tt.expectedQuery)
+		}
+		parameters := queryParameters(conn.Contexts()[0])
+		if len(parameters) != len(tt.expectedParameters) || (len(parameters) > 0 && !reflect.DeepEqual(parameters, tt.expectedParameters)) {
+			t.Errorf("GetDatabases() parameters = %v, expected %v", parameters, tt.expectedParameters)
+		}
+		if open := conn.OpenRows(); open != 0 {
+			t.Errorf("expected the rows to be closed, %d are open", open)
+		}
+	}
+}
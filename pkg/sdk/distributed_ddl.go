@@ -0,0 +1,250 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// distributedDDLOutputMode makes the ON CLUSTER statements return the outcome of every host: the
+// hosts which failed with their error instead of rethrowing the error of the first one, and the
+// hosts which didn't finish within `distributed_ddl_task_timeout` with a NULL status. It is only set
+// when the settings don't choose another mode.
+const distributedDDLOutputMode = "never_throw"
+
+// distributedDDLPollInterval is the pause between two reads of `system.distributed_ddl_queue`
+var distributedDDLPollInterval = time.Second
+
+var onClusterStatementRegexp = regexp.MustCompile(`(?i)\bON\s+CLUSTER\b`)
+
+// isOnClusterStatement tells whether the statement is a distributed DDL, executed by every host of a cluster
+func isOnClusterStatement(query string) bool {
+	return onClusterStatementRegexp.MatchString(query)
+}
+
+// DistributedDDLHost is the outcome of an ON CLUSTER statement on a host of the cluster
+type DistributedDDLHost struct {
+	Host string
+	Port uint16
+	// Status is the exception code of the host, 0 when it succeeded, and nil when it didn't finish
+	Status *int64
+	Error  string
+}
+
+func (h DistributedDDLHost) String() string {
+	switch {
+	case h.Status == nil:
+		return fmt.Sprintf("%s:%d: not finished", h.Host, h.Port)
+	case *h.Status == 0:
+		return fmt.Sprintf("%s:%d: OK", h.Host, h.Port)
+	default:
+		return fmt.Sprintf("%s:%d: code %d, %s", h.Host, h.Port, *h.Status, strings.TrimSpace(h.Error))
+	}
+}
+
+// DistributedDDLError is returned when an ON CLUSTER statement failed or didn't finish on some of
+// the hosts, it lists the outcome of every host
+type DistributedDDLError struct {
+	Hosts []DistributedDDLHost
+}
+
+func (e *DistributedDDLError) Error() string {
+	failed, unfinished := e.count()
+	var summary []string
+	if failed > 0 {
+		summary = append(summary, fmt.Sprintf("failed on %d of %d hosts", failed, len(e.Hosts)))
+	}
+	if unfinished > 0 {
+		summary = append(summary, fmt.Sprintf("didn't finish on %d of %d hosts", unfinished, len(e.Hosts)))
+	}
+
+	lines := []string{fmt.Sprintf("distributed DDL %s:", strings.Join(summary, " and "))}
+	for _, host := range e.Hosts {
+		lines = append(lines, "- "+host.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unfinished tells whether the statement didn't fail on any host, but some hosts didn't finish yet
+func (e *DistributedDDLError) Unfinished() bool {
+	failed, unfinished := e.count()
+	return failed == 0 && unfinished > 0
+}
+
+func (e *DistributedDDLError) count() (int, int) {
+	failed, unfinished := 0, 0
+	for _, host := range e.Hosts {
+		switch {
+		case host.Status == nil:
+			unfinished++
+		case *host.Status != 0:
+			failed++
+		}
+	}
+	return failed, unfinished
+}
+
+// distributedDDLError returns the error of the hosts, nil when they all succeeded
+func distributedDDLError(hosts []DistributedDDLHost) error {
+	ddlErr := &DistributedDDLError{Hosts: hosts}
+	if failed, unfinished := ddlErr.count(); failed == 0 && unfinished == 0 {
+		return nil
+	}
+	return ddlErr
+}
+
+type distributedDDLIDKey struct{}
+
+// newDistributedDDLID returns a random ID, sent in the `log_comment` of an ON CLUSTER statement to
+// find its entries in `system.distributed_ddl_queue`
+func newDistributedDDLID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// execOnCluster executes a distributed DDL and reads the result of every host. When some hosts
+// didn't finish and `DistributedDDLWaitTimeout` is set, their progress is then followed in
+// `system.distributed_ddl_queue`, the statement being told apart from the other ones by the
+// `log_comment` it is sent with.
+func (c *Client) execOnCluster(ctx context.Context, conn driver.Conn, query string, args ...any) error {
+	if c.DistributedDDLWaitTimeout > 0 {
+		ctx = context.WithValue(ctx, distributedDDLIDKey{}, newDistributedDDLID())
+	}
+	start := time.Now()
+	rows, err := conn.Query(c.queryContext(ctx, query, true), query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	hosts, err := scanDistributedDDLHosts(rows)
+	if err != nil {
+		return fmt.Errorf("reading the distributed DDL result: %w", err)
+	}
+
+	err = distributedDDLError(hosts)
+	if ddlErr, ok := err.(*DistributedDDLError); ok && ddlErr.Unfinished() && c.DistributedDDLWaitTimeout > 0 {
+		return c.waitDistributedDDL(ctx, hosts, start, fmt.Sprint(c.querySettings(ctx, query)["log_comment"]))
+	}
+	return err
+}
+
+// scanDistributedDDLHosts reads the rows returned by an ON CLUSTER statement, one per host
+func scanDistributedDDLHosts(rows driver.Rows) ([]DistributedDDLHost, error) {
+	columns := rows.Columns()
+	var hosts []DistributedDDLHost
+	for rows.Next() {
+		var host DistributedDDLHost
+		var errorText *string
+		var discarded uint64
+		dest := make([]any, len(columns))
+		for i, column := range columns {
+			switch column {
+			case "host":
+				dest[i] = &host.Host
+			case "port":
+				dest[i] = &host.Port
+			case "status":
+				dest[i] = &host.Status
+			case "error":
+				dest[i] = &errorText
+			default:
+				// num_hosts_remaining and num_hosts_active
+				dest[i] = &discarded
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if errorText != nil {
+			host.Error = *errorText
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, rows.Err()
+}
+
+// waitDistributedDDL follows the hosts which didn't finish in `system.distributed_ddl_queue`, until
+// they all finish or `DistributedDDLWaitTimeout` is reached. The entries are those of the statement,
+// sent with the `log_comment`, created since it was executed.
+func (c *Client) waitDistributedDDL(ctx context.Context, hosts []DistributedDDLHost, start time.Time, logComment string) error {
+	deadline := start.Add(c.DistributedDDLWaitTimeout)
+	for {
+		pending := 0
+		for _, host := range hosts {
+			if host.Status == nil {
+				pending++
+			}
+		}
+		if pending == 0 || time.Now().After(deadline) {
+			return distributedDDLError(hosts)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("waiting for the distributed DDL on %d hosts", pending))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(distributedDDLPollInterval):
+		}
+
+		if err := c.readDistributedDDLQueue(ctx, hosts, start, logComment); err != nil {
+			return err
+		}
+	}
+}
+
+// readDistributedDDLQueue updates the hosts which didn't finish with the latest entry of the
+// statement in `system.distributed_ddl_queue`
+func (c *Client) readDistributedDDLQueue(ctx context.Context, hosts []DistributedDDLHost, start time.Time, logComment string) error {
+	// The age is measured with the server clock, which may differ from the local one
+	age := uint64(math.Ceil(time.Since(start).Seconds())) + 1
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"age": fmt.Sprint(age), "log_comment": logComment}),
+		"SELECT ifNull(host, '') AS host, ifNull(port, 0) AS port, ifNull(toString(status), '') AS status, toInt64(ifNull(exception_code, 0)) AS exception_code, ifNull(exception_text, '') AS exception_text "+
+			"FROM system.distributed_ddl_queue WHERE query_create_time >= now() - toIntervalSecond({age:UInt64}) AND settings['log_comment'] = {log_comment:String} ORDER BY entry DESC",
+	)
+	if err != nil {
+		return fmt.Errorf("reading system.distributed_ddl_queue: %w", err)
+	}
+	defer rows.Close()
+
+	seen := map[string]bool{}
+	for rows.Next() {
+		var host, status, exceptionText string
+		var port uint16
+		var exceptionCode int64
+		if err := rows.Scan(&host, &port, &status, &exceptionCode, &exceptionText); err != nil {
+			return fmt.Errorf("scanning system.distributed_ddl_queue row: %w", err)
+		}
+		// Only the latest entry of a host matters
+		key := fmt.Sprintf("%s:%d", host, port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		for i := range hosts {
+			if hosts[i].Status != nil || hosts[i].Host != host || hosts[i].Port != port {
+				continue
+			}
+			if status == "Finished" || exceptionCode != 0 {
+				code := exceptionCode
+				hosts[i].Status = &code
+				hosts[i].Error = exceptionText
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading system.distributed_ddl_queue: %w", err)
+	}
+	return nil
+}
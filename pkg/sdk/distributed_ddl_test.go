@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+const onClusterStatement = "CREATE DATABASE `analytics` ON CLUSTER `main`"
+
+// distributedDDLColumns are the columns returned by an ON CLUSTER statement
+var distributedDDLColumns = []string{"host", "port", "status", "error", "num_hosts_remaining", "num_hosts_active"}
+
+// newOnClusterClient returns a client on the connection, which fails the ON CLUSTER statements
+// according to their distributed_ddl_output_mode like the server
+func newOnClusterClient(conn *sdktest.FakeConn) *Client {
+	client := NewClientWithConn(conn)
+	conn.WithSettings(client.querySettings)
+	return client
+}
+
+func TestExecOnCluster(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rows        [][]any
+		expectedErr string
+		unfinished  bool
+	}{
+		{
+			name: "success",
+			rows: [][]any{
+				{"ch-1", uint16(9000), int64(0), "", uint64(1), uint64(0)},
+				{"ch-2", uint16(9000), int64(0), "", uint64(0), uint64(0)},
+			},
+		},
+		{
+			name: "failed host",
+			rows: [][]any{
+				{"ch-1", uint16(9000), int64(0), "", uint64(1), uint64(0)},
+				{"ch-2", uint16(9000), int64(57), "Code: 57. DB::Exception: Database analytics already exists. ", uint64(0), uint64(0)},
+			},
+			expectedErr: "distributed DDL failed on 1 of 2 hosts:\n- ch-1:9000: OK\n- ch-2:9000: code 57, Code: 57. DB::Exception: Database analytics already exists.",
+		},
+		{
+			name: "failed and unfinished hosts",
+			rows: [][]any{
+				{"ch-1", uint16(9000), int64(159), "Timeout exceeded", uint64(2), uint64(1)},
+				{"ch-2", uint16(9000), nil, nil, uint64(1), uint64(1)},
+				{"ch-3", uint16(9000), int64(0), "", uint64(0), uint64(0)},
+			},
+			expectedErr: "distributed DDL failed on 1 of 3 hosts and didn't finish on 1 of 3 hosts:\n- ch-1:9000: code 159, Timeout exceeded\n- ch-2:9000: not finished\n- ch-3:9000: OK",
+		},
+		{
+			name: "unfinished host",
+			rows: [][]any{
+				{"ch-1", uint16(9000), int64(0), "", uint64(1), uint64(1)},
+				{"ch-2", uint16(9000), nil, nil, uint64(1), uint64(1)},
+			},
+			expectedErr: "distributed DDL didn't finish on 1 of 2 hosts:\n- ch-1:9000: OK\n- ch-2:9000: not finished",
+			unfinished:  true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := sdktest.NewFakeConn().AddRows(`ON CLUSTER`, distributedDDLColumns, tt.rows...)
+			client := newOnClusterClient(conn)
+			client.MaxRetries = 0
+
+			err := client.Exec(context.Background(), onClusterStatement)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Exec() error = %v", err)
+				}
+				return
+			}
+
+			var ddlErr *DistributedDDLError
+			if !errors.As(err, &ddlErr) {
+				t.Fatalf("Exec() error = %v, expected a DistributedDDLError", err)
+			}
+			if ddlErr.Error() != tt.expectedErr {
+				t.Errorf("Exec() error =\n%s\nexpected\n%s", ddlErr.Error(), tt.expectedErr)
+			}
+			if ddlErr.Unfinished() != tt.unfinished {
+				t.Errorf("Unfinished() = %t, expected %t", ddlErr.Unfinished(), tt.unfinished)
+			}
+			if queries := conn.Queries(); len(queries) != 1 {
+				t.Errorf("expected only the statement to be executed, got %v", queries)
+			}
+		})
+	}
+}
+
+func TestExecOnClusterOutputMode(t *testing.T) {
+	conn := sdktest.NewFakeConn().AddRows(`ON CLUSTER`, distributedDDLColumns,
+		[]any{"ch-1", uint16(9000), int64(0), "", uint64(1), uint64(0)},
+		[]any{"ch-2", uint16(9000), int64(57), "Code: 57. DB::Exception: Database analytics already exists. ", uint64(0), uint64(0)},
+	)
+	client := newOnClusterClient(conn)
+	client.MaxRetries = 0
+
+	// A mode chosen by the settings rethrows the error of the first failing host
+	ctx := WithQuerySettings(context.Background(), map[string]string{"distributed_ddl_output_mode": "throw"})
+	var exception *clickhouse.Exception
+	if err := client.Exec(ctx, onClusterStatement); !errors.As(err, &exception) || exception.Code != 57 {
+		t.Errorf("Exec() error = %v, expected the exception of ch-2", err)
+	}
+}
+
+func TestExecOnClusterWait(t *testing.T) {
+	defer func(interval time.Duration) { distributedDDLPollInterval = interval }(distributedDDLPollInterval)
+	distributedDDLPollInterval = time.Millisecond
+
+	unfinished := [][]any{
+		{"ch-1", uint16(9000), int64(0), "", uint64(1), uint64(1)},
+		{"ch-2", uint16(9000), nil, nil, uint64(1), uint64(1)},
+	}
+	queueColumns := []string{"host", "port", "status", "exception_code", "exception_text"}
+
+	t.Run("finished", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRows(`ON CLUSTER`, distributedDDLColumns, unfinished...).
+			AddRows(`system\.distributed_ddl_queue`, queueColumns,
+				[]any{"ch-2", uint16(9000), "Finished", int64(0), ""},
+				[]any{"ch-2", uint16(9000), "Active", int64(0), ""},
+				[]any{"ch-1", uint16(9000), "Finished", int64(0), ""},
+			)
+		client := newOnClusterClient(conn)
+		client.DistributedDDLWaitTimeout = time.Minute
+
+		if err := client.Exec(context.Background(), onClusterStatement); err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+		queries := conn.Queries()
+		if len(queries) != 2 || !strings.Contains(queries[1], "system.distributed_ddl_queue") {
+			t.Fatalf("expected system.distributed_ddl_queue to be read once, got %v", queries)
+		}
+
+		// The queue entries are those of the statement, sent with its log_comment
+		contexts := conn.Contexts()
+		logComment, _ := client.querySettings(contexts[0], queries[0])["log_comment"].(string)
+		if !strings.Contains(logComment, `"distributed_ddl_id":`) {
+			t.Errorf("expected the statement log_comment to carry a distributed DDL ID, got %q", logComment)
+		}
+		if parameter := queryParameters(contexts[1])["log_comment"]; parameter != logComment || !strings.Contains(queries[1], "settings['log_comment'] = {log_comment:String}") {
+			t.Errorf("expected system.distributed_ddl_queue to be filtered on the log_comment %q, got %q with %q", logComment, queries[1], parameter)
+		}
+		if open := conn.OpenRows(); open != 0 {
+			t.Errorf("expected the rows to be closed, %d are open", open)
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRows(`ON CLUSTER`, distributedDDLColumns, unfinished...).
+			AddRows(`system\.distributed_ddl_queue`, queueColumns[:4],
+				[]any{"ch-2", uint16(9000), "Finished", int64(0)},
+			)
+		client := newOnClusterClient(conn)
+		client.MaxRetries = 0
+		client.DistributedDDLWaitTimeout = time.Minute
+
+		err := client.Exec(context.Background(), onClusterStatement)
+		if err == nil || !strings.Contains(err.Error(), "scanning system.distributed_ddl_queue row") {
+			t.Errorf("Exec() error = %v, expected the scan error", err)
+		}
+		if open := conn.OpenRows(); open != 0 {
+			t.Errorf("expected the rows to be closed, %d are open", open)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRows(`ON CLUSTER`, distributedDDLColumns, unfinished...).
+			AddRows(`system\.distributed_ddl_queue`, queueColumns,
+				[]any{"ch-2", uint16(9000), "Finished", int64(241), "Memory limit exceeded"},
+			)
+		client := newOnClusterClient(conn)
+		client.MaxRetries = 0
+		client.DistributedDDLWaitTimeout = time.Minute
+
+		err := client.Exec(context.Background(), onClusterStatement)
+		if err == nil || !strings.Contains(err.Error(), "- ch-2:9000: code 241, Memory limit exceeded") {
+			t.Errorf("Exec() error = %v, expected the failure of ch-2", err)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRows(`ON CLUSTER`, distributedDDLColumns, unfinished...).
+			AddRows(`system\.distributed_ddl_queue`, queueColumns,
+				[]any{"ch-2", uint16(9000), "Active", int64(0), ""},
+			)
+		client := newOnClusterClient(conn)
+		client.MaxRetries = 0
+		client.DistributedDDLWaitTimeout = 20 * time.Millisecond
+
+		var ddlErr *DistributedDDLError
+		if err := client.Exec(context.Background(), onClusterStatement); !errors.As(err, &ddlErr) || !ddlErr.Unfinished() {
+			t.Errorf("Exec() error = %v, expected ch-2 to be unfinished", err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// recordStatement appends the statement to `SQLOutputFile` instead of executing it, or only logs
+// it when no file is configured
+func (c *Client) recordStatement(ctx context.Context, query string) error {
+	statement := strings.TrimSpace(query)
+	tflog.Info(ctx, "dry run, recording statement instead of executing it", map[string]interface{}{
+		"query": statement,
+	})
+	if c.SQLOutputFile == "" {
+		return nil
+	}
+
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	file, err := os.OpenFile(c.SQLOutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening sql_output_file: %w", err)
+	}
+	if _, err := fmt.Fprintf(file, "%s;\n", statement); err != nil {
+		file.Close()
+		return fmt.Errorf("writing statement to sql_output_file: %w", err)
+	}
+	return file.Close()
+}
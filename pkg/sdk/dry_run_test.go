@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "migration.sql")
+	// Write statements must not open the connection
+	client := &Client{DryRun: true, SQLOutputFile: outputFile, ConfigError: errors.New("no server")}
+
+	if err := client.Exec(context.Background(), "  DROP TABLE db.t  "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	role, err := client.CreateRole(context.Background(), "reader", "", "db", []string{"SELECT"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.Name != "reader" {
+		t.Errorf("expected role reader, got %s", role.Name)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("reading output file: %v", err)
+	}
+	expected := "DROP TABLE db.t;\nCREATE ROLE `reader`;\nGRANT SELECT ON `db`.* TO `reader`;\n"
+	if string(content) != expected {
+		t.Errorf("expected output file content %q, got %q", expected, string(content))
+	}
+}
@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ClickhouseClient is the set of operations the resources and data sources run on Clickhouse. The
+// provider configures a *Client, tests may provide their own implementation or a *Client running
+// on a fake connection, see NewClientWithConn.
+type ClickhouseClient interface {
+	GetCluster(cluster string) string
+	GetCreateMode(createMode string) string
+	IsDryRun() bool
+
+	Exec(ctx context.Context, query string, args ...any) error
+	Query(ctx context.Context, query string, args ...any) (driver.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) driver.Row
+	ServerVersion(ctx context.Context) (ServerVersion, error)
+
+	GetDatabase(ctx context.Context, name string) (*models.CHDatabase, error)
+	GetDatabases(ctx context.Context, filter models.DatabaseFilter) ([]models.CHDatabase, error)
+	CreateDatabase(ctx context.Context, database models.DatabaseResource) error
+	UpdateDatabase(ctx context.Context, database models.DatabaseResource, previous models.DatabaseResource) error
+	DeleteDatabase(ctx context.Context, name string, cluster string) error
+	GetDBTables(ctx context.Context, database string) ([]models.CHTable, error)
+	GetDatabaseReplicaDrift(ctx context.Context, cluster string, database string) (ReplicaDrift, error)
+	GetDatabaseObjects(ctx context.Context, database string) ([]models.CHDatabaseObject, error)
+	DropDatabaseObjects(ctx context.Context, cluster string, objects []models.CHDatabaseObject) error
+
+	GetTable(ctx context.Context, database string, table string) (*models.CHTable, error)
+	CreateTable(ctx context.Context, tableResource models.TableResource) error
+	UpdateTable(ctx context.Context, table models.TableResource, resourceData *schema.ResourceData) error
+	DeleteTable(ctx context.Context, tableResource models.TableResource) error
+	GetColumnDefintions(columns []models.ColumnDefinition) []map[string]interface{}
+	GetIndexDefintions(indexes []models.IndexDefinition) []map[string]interface{}
+	GetTableReplicaDrift(ctx context.Context, cluster string, database string, name string) (ReplicaDrift, error)
+	GetMutations(ctx context.Context, cluster string, database string, table string) ([]models.CHMutation, error)
+	WaitForMutations(ctx context.Context, cluster string, database string, table string, mutations []models.CHMutation) error
+
+	GetView(ctx context.Context, database string, view string) (*models.CHView, error)
+	CreateView(ctx context.Context, resource models.ViewResource) error
+	DeleteView(ctx context.Context, resource models.ViewResource) error
+
+	GetRole(ctx context.Context, roleName string) (*models.CHRole, error)
+	CreateRole(ctx context.Context, name string, cluster string, database string, privileges []string) (*models.CHRole, error)
+	UpdateRole(ctx context.Context, rolePlan models.RoleResource, resourceData *schema.ResourceData) (*models.CHRole, error)
+	DeleteRole(ctx context.Context, name string, cluster string) error
+
+	GetUser(ctx context.Context, userName string) (*models.CHUser, error)
+	CreateUser(ctx context.Context, userPlan models.UserResource) (*models.CHUser, error)
+	UpdateUser(ctx context.Context, userPlan models.UserResource, resourceData *schema.ResourceData) (*models.CHUser, error)
+	DeleteUser(ctx context.Context, name string, cluster string) error
+}
+
+var _ ClickhouseClient = &Client{}
@@ -0,0 +1,159 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// mutationPollInterval is the pause between two reads of `system.mutations`
+var mutationPollInterval = time.Second
+
+// MutationError is returned when mutations of a table can't be applied, with the `latest_fail_reason`
+// of each of them
+type MutationError struct {
+	Database  string
+	Table     string
+	Mutations []models.CHMutation
+}
+
+func (e *MutationError) Error() string {
+	lines := []string{fmt.Sprintf("mutations of %s.%s failing:", e.Database, e.Table)}
+	for _, mutation := range e.Mutations {
+		lines = append(lines, fmt.Sprintf("- %s on %s (%s): %s", mutation.MutationID, mutation.Host, mutation.Command, strings.TrimSpace(mutation.LatestFailReason)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetMutations reads the mutations of a table, on every replica of the cluster when one is set
+func (c *Client) GetMutations(ctx context.Context, cluster string, database string, table string) ([]models.CHMutation, error) {
+	from := "system.mutations"
+	if cluster != "" {
+		from = fmt.Sprintf("clusterAllReplicas(%s, system.mutations)", common.QuoteCluster(cluster))
+	}
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"database": database, "table": table}),
+		fmt.Sprintf("SELECT hostName() AS host, mutation_id, command, is_done, latest_fail_reason FROM %s WHERE database = {database:String} AND table = {table:String} ORDER BY host, create_time", from),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading mutations from Clickhouse: %v", err)
+	}
+	defer rows.Close()
+
+	var mutations []models.CHMutation
+	for rows.Next() {
+		var mutation models.CHMutation
+		if err := rows.ScanStruct(&mutation); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse mutation row: %v", err)
+		}
+		mutations = append(mutations, mutation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading mutations from Clickhouse: %v", err)
+	}
+	return mutations, nil
+}
+
+// NewMutations returns the mutations which are not part of the previous ones, i.e. the ones started
+// by the statements executed since the previous ones were read
+func NewMutations(previous []models.CHMutation, current []models.CHMutation) []models.CHMutation {
+	known := map[string]bool{}
+	for _, mutation := range previous {
+		known[mutation.Host+"/"+mutation.MutationID] = true
+	}
+	var started []models.CHMutation
+	for _, mutation := range current {
+		if !known[mutation.Host+"/"+mutation.MutationID] {
+			started = append(started, mutation)
+		}
+	}
+	return started
+}
+
+// PendingMutations returns the mutations which are not done yet
+func PendingMutations(mutations []models.CHMutation) []models.CHMutation {
+	var pending []models.CHMutation
+	for _, mutation := range mutations {
+		if !mutation.IsDone {
+			pending = append(pending, mutation)
+		}
+	}
+	return pending
+}
+
+// MutationIDs returns the sorted IDs of the mutations, a mutation of a replicated table having the
+// same ID on every replica
+func MutationIDs(mutations []models.CHMutation) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, mutation := range mutations {
+		if !seen[mutation.MutationID] {
+			seen[mutation.MutationID] = true
+			ids = append(ids, mutation.MutationID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CheckMutations returns the error of the mutations which are not done and failed at least once
+func CheckMutations(database string, table string, mutations []models.CHMutation) error {
+	var failed []models.CHMutation
+	for _, mutation := range mutations {
+		if !mutation.IsDone && mutation.LatestFailReason != "" {
+			failed = append(failed, mutation)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &MutationError{Database: database, Table: table, Mutations: failed}
+}
+
+// WaitForMutations waits until the mutations of the table are done, or the context is done. It
+// fails as soon as one of them reports a `latest_fail_reason`, Clickhouse would otherwise retry it
+// forever.
+func (c *Client) WaitForMutations(ctx context.Context, cluster string, database string, table string, mutations []models.CHMutation) error {
+	waiting := map[string]bool{}
+	for _, mutation := range mutations {
+		waiting[mutation.Host+"/"+mutation.MutationID] = true
+	}
+
+	for {
+		current, err := c.GetMutations(ctx, cluster, database, table)
+		if err != nil {
+			return err
+		}
+		var tracked []models.CHMutation
+		for _, mutation := range current {
+			if waiting[mutation.Host+"/"+mutation.MutationID] {
+				tracked = append(tracked, mutation)
+			}
+		}
+		if err := CheckMutations(database, table, tracked); err != nil {
+			return err
+		}
+		pending := PendingMutations(tracked)
+		if len(pending) == 0 {
+			return nil
+		}
+		tflog.Debug(ctx, fmt.Sprintf("waiting for %d mutations of %s.%s", len(pending), database, table))
+
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = errors.New("timeout exceeded")
+			}
+			return fmt.Errorf("waiting for the mutations %s of %s.%s: %v", strings.Join(MutationIDs(pending), ", "), database, table, err)
+		case <-time.After(mutationPollInterval):
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+var mutationColumns = []string{"host", "mutation_id", "command", "is_done", "latest_fail_reason"}
+
+func TestGetMutations(t *testing.T) {
+	conn := sdktest.NewFakeConn().AddRows(`system\.mutations`, mutationColumns,
+		[]any{"ch-1", "0000000001", "MODIFY COLUMN `event_type` Int64", true, ""},
+		[]any{"ch-2", "0000000001", "MODIFY COLUMN `event_type` Int64", false, ""},
+	)
+
+	mutations, err := NewClientWithConn(conn).GetMutations(context.Background(), "main", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetMutations() error = %v", err)
+	}
+	if len(mutations) != 2 || !mutations[0].IsDone || mutations[1].Host != "ch-2" {
+		t.Errorf("GetMutations() = %v", mutations)
+	}
+	if query := conn.Queries()[0]; !strings.Contains(query, "clusterAllReplicas(`main`, system.mutations)") {
+		t.Errorf("expected the mutations to be read on the replicas, got %s", query)
+	}
+	if open := conn.OpenRows(); open != 0 {
+		t.Errorf("expected the rows to be closed, %d are still open", open)
+	}
+
+	previous := []models.CHMutation{{Host: "ch-1", MutationID: "0000000000"}}
+	started := NewMutations(previous, append(previous, mutations...))
+	if !reflect.DeepEqual(started, mutations) {
+		t.Errorf("NewMutations() = %v, expected %v", started, mutations)
+	}
+	if ids := MutationIDs(PendingMutations(started)); !reflect.DeepEqual(ids, []string{"0000000001"}) {
+		t.Errorf("pending MutationIDs() = %v, expected [0000000001]", ids)
+	}
+}
+
+func TestWaitForMutations(t *testing.T) {
+	defer func(interval time.Duration) { mutationPollInterval = interval }(mutationPollInterval)
+	mutationPollInterval = time.Millisecond
+
+	started := []models.CHMutation{{Host: "ch-1", MutationID: "mutation_2.txt"}}
+
+	t.Run("done", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().
+			AddRowsOnce(`system\.mutations`, mutationColumns, []any{"ch-1", "mutation_2.txt", "MATERIALIZE TTL", false, ""}).
+			AddRows(`system\.mutations`, mutationColumns,
+				[]any{"ch-1", "mutation_1.txt", "DELETE WHERE 1", false, "Stuck"},
+				[]any{"ch-1", "mutation_2.txt", "MATERIALIZE TTL", true, ""},
+			)
+
+		if err := NewClientWithConn(conn).WaitForMutations(context.Background(), "", "analytics", "events", started); err != nil {
+			t.Fatalf("WaitForMutations() error = %v", err)
+		}
+		if queries := conn.Queries(); len(queries) != 2 {
+			t.Errorf("expected system.mutations to be read twice, got %v", queries)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().AddRows(`system\.mutations`, mutationColumns,
+			[]any{"ch-1", "mutation_2.txt", "MATERIALIZE TTL", false, "Code: 241. DB::Exception: Memory limit exceeded"},
+		)
+
+		err := NewClientWithConn(conn).WaitForMutations(context.Background(), "", "analytics", "events", started)
+		var mutationErr *MutationError
+		if !errors.As(err, &mutationErr) {
+			t.Fatalf("WaitForMutations() error = %v, expected a MutationError", err)
+		}
+		expected := "mutations of analytics.events failing:\n- mutation_2.txt on ch-1 (MATERIALIZE TTL): Code: 241. DB::Exception: Memory limit exceeded"
+		if err.Error() != expected {
+			t.Errorf("WaitForMutations() error =\n%s\nexpected\n%s", err, expected)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		conn := sdktest.NewFakeConn().AddRows(`system\.mutations`, mutationColumns,
+			[]any{"ch-1", "mutation_2.txt", "MATERIALIZE TTL", false, ""},
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := NewClientWithConn(conn).WaitForMutations(ctx, "", "analytics", "events", started)
+		if err == nil || !strings.Contains(err.Error(), "waiting for the mutations mutation_2.txt of analytics.events: timeout exceeded") {
+			t.Errorf("WaitForMutations() error = %v, expected a timeout", err)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+)
+
+// DriftMissing is the drift of a host on which the object doesn't exist
+const DriftMissing = "missing"
+
+// ReplicaDrift maps the hosts of a cluster whose copy of an object differs from the reference copy
+// to a description of the difference, DriftMissing or the attributes that differ. The reference is
+// the copy of the connected host, or of the first host having the object when the connected host
+// is not part of the cluster. Hosts in sync are not listed.
+type ReplicaDrift map[string]string
+
+// MissingHosts returns the sorted hosts on which the object is missing
+func (d ReplicaDrift) MissingHosts() []string {
+	var hosts []string
+	for host, drift := range d {
+		if drift == DriftMissing {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// String lists the drift of every host, one per line
+func (d ReplicaDrift) String() string {
+	hosts := make([]string, 0, len(d))
+	for host := range d {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	lines := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		lines = append(lines, fmt.Sprintf("%s: %s", host, d[host]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// replicaAttribute is an attribute of an object compared across the replicas, read with a SQL
+// expression on the `system` table describing the object
+type replicaAttribute struct {
+	name       string
+	expression string
+}
+
+// GetTableReplicaDrift compares a table or a view across the replicas of the cluster: its engine,
+// query, comment and columns
+func (c *Client) GetTableReplicaDrift(ctx context.Context, cluster string, database string, name string) (ReplicaDrift, error) {
+	ctx = WithParameters(ctx, map[string]string{"database": database, "name": name})
+
+	replicas, err := c.replicaValues(ctx, cluster, "system.tables", []replicaAttribute{
+		{name: "engine", expression: "engine_full"},
+		{name: "query", expression: "as_select"},
+		{name: "comment", expression: "comment"},
+	}, "database = {database:String} AND name = {name:String}", "")
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := c.replicaValues(ctx, cluster, "system.columns", []replicaAttribute{
+		{name: "columns", expression: "concat(name, ' ', type)"},
+	}, "database = {database:String} AND table = {name:String}", "position")
+	if err != nil {
+		return nil, err
+	}
+	for host, values := range replicas {
+		values["columns"] = columns[host]["columns"]
+	}
+
+	return c.replicaDrift(ctx, cluster, replicas)
+}
+
+// GetDatabaseReplicaDrift compares a database across the replicas of the cluster: its engine and comment
+func (c *Client) GetDatabaseReplicaDrift(ctx context.Context, cluster string, database string) (ReplicaDrift, error) {
+	ctx = WithParameters(ctx, map[string]string{"name": database})
+
+	replicas, err := c.replicaValues(ctx, cluster, "system.databases", []replicaAttribute{
+		{name: "engine", expression: "engine"},
+		{name: "comment", expression: "comment"},
+	}, "name = {name:String}", "")
+	if err != nil {
+		return nil, err
+	}
+	return c.replicaDrift(ctx, cluster, replicas)
+}
+
+// replicaValues reads the attributes of an object on every replica of the cluster, by host. The
+// values of the rows of a host are joined, e.g. the columns of a table.
+func (c *Client) replicaValues(ctx context.Context, cluster string, systemTable string, attributes []replicaAttribute, where string, orderBy string) (map[string]map[string]string, error) {
+	expressions := []string{"hostName() AS host"}
+	for _, attribute := range attributes {
+		expressions = append(expressions, fmt.Sprintf("toString(%s) AS %s", attribute.expression, common.QuoteIdentifier(attribute.name)))
+	}
+	order := "host"
+	if orderBy != "" {
+		order += ", " + orderBy
+	}
+	query := fmt.Sprintf(
+		"SELECT %s FROM clusterAllReplicas(%s, %s) WHERE %s ORDER BY %s",
+		strings.Join(expressions, ", "),
+		common.QuoteCluster(cluster),
+		systemTable,
+		where,
+		order,
+	)
+
+	rows, err := c.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s on the replicas of cluster %s: %v", systemTable, cluster, err)
+	}
+	defer rows.Close()
+
+	replicas := map[string]map[string]string{}
+	for rows.Next() {
+		var host string
+		values := make([]string, len(attributes))
+		dest := []any{&host}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning %s row: %v", systemTable, err)
+		}
+
+		replica, ok := replicas[host]
+		if !ok {
+			replica = map[string]string{}
+			replicas[host] = replica
+		}
+		for i, attribute := range attributes {
+			if previous, ok := replica[attribute.name]; ok {
+				replica[attribute.name] = previous + ", " + values[i]
+			} else {
+				replica[attribute.name] = values[i]
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s on the replicas of cluster %s: %v", systemTable, cluster, err)
+	}
+	return replicas, nil
+}
+
+// replicaDrift compares the values read on the replicas with the ones of the reference host
+func (c *Client) replicaDrift(ctx context.Context, cluster string, replicas map[string]map[string]string) (ReplicaDrift, error) {
+	rows, err := c.Query(ctx, fmt.Sprintf("SELECT DISTINCT hostName() AS host FROM clusterAllReplicas(%s, system.one) ORDER BY host", common.QuoteCluster(cluster)))
+	if err != nil {
+		return nil, fmt.Errorf("reading the replicas of cluster %s: %v", cluster, err)
+	}
+	defer rows.Close()
+	var hosts []string
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err != nil {
+			return nil, fmt.Errorf("scanning host row: %v", err)
+		}
+		hosts = append(hosts, host)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading the replicas of cluster %s: %v", cluster, err)
+	}
+
+	var localHost string
+	if err := c.QueryRow(ctx, "SELECT hostName()").Scan(&localHost); err != nil {
+		return nil, fmt.Errorf("reading the connected host: %v", err)
+	}
+	reference, ok := replicas[localHost]
+	if !ok {
+		for _, host := range hosts {
+			if reference, ok = replicas[host]; ok {
+				break
+			}
+		}
+	}
+
+	drift := ReplicaDrift{}
+	for _, host := range hosts {
+		values, ok := replicas[host]
+		if !ok {
+			drift[host] = DriftMissing
+			continue
+		}
+		var differences []string
+		for name, value := range reference {
+			if values[name] != value {
+				differences = append(differences, name)
+			}
+		}
+		if len(differences) > 0 {
+			sort.Strings(differences)
+			drift[host] = "different " + strings.Join(differences, ", ")
+		}
+	}
+	return drift, nil
+}
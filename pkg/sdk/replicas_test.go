@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+// replicatedTable registers the copies of analytics.events on the replicas of the cluster main
+func replicatedTable(conn *sdktest.FakeConn) *sdktest.FakeConn {
+	return conn.
+		AddRows(`^SELECT hostName\(\)$`, []string{"hostName()"}, []any{"ch-1"}).
+		AddRows(`clusterAllReplicas\(.main., system\.one\)`, []string{"host"}, []any{"ch-1"}, []any{"ch-2"}, []any{"ch-3"}).
+		AddRows(`clusterAllReplicas\(.main., system\.tables\)`,
+			[]string{"host", "engine", "query", "comment"},
+			[]any{"ch-1", "MergeTree ORDER BY event_date", "", "Raw events"},
+			[]any{"ch-3", "MergeTree ORDER BY event_date", "", "Events"},
+		).
+		AddRows(`clusterAllReplicas\(.main., system\.columns\)`,
+			[]string{"host", "columns"},
+			[]any{"ch-1", "event_date Date"},
+			[]any{"ch-1", "event_type Int32"},
+			[]any{"ch-3", "event_date Date"},
+		)
+}
+
+func TestGetTableReplicaDrift(t *testing.T) {
+	conn := replicatedTable(sdktest.NewFakeConn())
+
+	drift, err := NewClientWithConn(conn).GetTableReplicaDrift(context.Background(), "main", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTableReplicaDrift() error = %v", err)
+	}
+	expected := ReplicaDrift{"ch-2": DriftMissing, "ch-3": "different columns, comment"}
+	if !reflect.DeepEqual(drift, expected) {
+		t.Errorf("GetTableReplicaDrift() = %v, expected %v", drift, expected)
+	}
+	if hosts := drift.MissingHosts(); !reflect.DeepEqual(hosts, []string{"ch-2"}) {
+		t.Errorf("MissingHosts() = %v, expected [ch-2]", hosts)
+	}
+	if open := conn.OpenRows(); open != 0 {
+		t.Errorf("expected the rows to be closed, %d are still open", open)
+	}
+}
+
+func TestGetDatabaseReplicaDrift(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`^SELECT hostName\(\)$`, []string{"hostName()"}, []any{"ch-1"}).
+		AddRows(`system\.one`, []string{"host"}, []any{"ch-1"}, []any{"ch-2"}).
+		AddRows(`system\.databases`, []string{"host", "engine", "comment"}, []any{"ch-1", "Atomic", ""}, []any{"ch-2", "Atomic", ""})
+
+	drift, err := NewClientWithConn(conn).GetDatabaseReplicaDrift(context.Background(), "main", "analytics")
+	if err != nil || len(drift) != 0 {
+		t.Errorf("expected no drift, got %v, %v", drift, err)
+	}
+	if open := conn.OpenRows(); open != 0 {
+		t.Errorf("expected the rows to be closed, %d are still open", open)
+	}
+
+	_, err = NewClientWithConn(sdktest.NewFakeConn().FailOn(`clusterAllReplicas`, errors.New("unknown cluster"))).
+		GetDatabaseReplicaDrift(context.Background(), "main", "analytics")
+	if err == nil {
+		t.Error("expected the error of the replicas query")
+	}
+}
@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxRetryBackoff caps the exponential backoff between two attempts
+const maxRetryBackoff = 30 * time.Second
+
+// retryableExceptionCodes lists the Clickhouse exception codes caused by a transient condition,
+// any other exception (syntax error, unknown table, access denied...) is fatal.
+// See https://github.com/ClickHouse/ClickHouse/blob/master/src/Common/ErrorCodes.cpp
+var retryableExceptionCodes = map[int32]string{
+	3:   "UNEXPECTED_END_OF_FILE",
+	159: "TIMEOUT_EXCEEDED",
+	202: "TOO_MANY_SIMULTANEOUS_QUERIES",
+	203: "NO_FREE_CONNECTION",
+	209: "SOCKET_TIMEOUT",
+	216: "QUERY_WITH_SAME_ID_IS_ALREADY_RUNNING",
+	210: "NETWORK_ERROR",
+	236: "ABORTED",
+	242: "TABLE_IS_READ_ONLY",
+	285: "TOO_FEW_LIVE_REPLICAS",
+	319: "UNKNOWN_STATUS_OF_INSERT",
+	425: "SYSTEM_ERROR",
+	473: "DEADLOCK_AVOIDED",
+	517: "CANNOT_ASSIGN_ALTER",
+	999: "KEEPER_EXCEPTION",
+}
+
+// httpExceptionCodeRegexp extracts the exception code from the errors of the HTTP interface,
+// which are returned as plain text instead of a clickhouse.Exception
+var httpExceptionCodeRegexp = regexp.MustCompile(`Code: (\d+)\.`)
+
+// nonIdempotentStatementRegexp matches the statements that can't be safely executed twice, e.g.
+// because the first attempt may have succeeded although the client saw an error
+var nonIdempotentStatementRegexp = regexp.MustCompile(`(?i)^\s*(CREATE|DROP|RENAME|EXCHANGE|INSERT|UNDROP)\b|\b(ADD|DROP|RENAME)\s+(COLUMN|INDEX|PROJECTION|CONSTRAINT)\b|\bRENAME\s+TO\b`)
+
+// isRetryableError tells whether the error is caused by a transient condition (network blip,
+// replica restarting, distributed DDL timeout...) worth retrying
+func isRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// The hosts which didn't finish within distributed_ddl_task_timeout may catch up
+	var ddlErr *DistributedDDLError
+	if errors.As(err, &ddlErr) {
+		return ddlErr.Unfinished()
+	}
+
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		_, retryable := retryableExceptionCodes[exception.Code]
+		return retryable
+	}
+	if match := httpExceptionCodeRegexp.FindStringSubmatch(err.Error()); match != nil {
+		code, _ := strconv.ParseInt(match[1], 10, 32)
+		_, retryable := retryableExceptionCodes[int32(code)]
+		return retryable
+	}
+
+	var netErr net.Error
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, clickhouse.ErrAcquireConnTimeout) ||
+		errors.As(err, &netErr)
+}
+
+// isIdempotentStatement tells whether executing the statement a second time has the same effect as
+// executing it once. CREATE OR REPLACE, CREATE ... IF NOT EXISTS and DROP ... IF EXISTS are, as well
+// as reads, grants and ALTER ... MODIFY statements.
+func isIdempotentStatement(query string) bool {
+	upperQuery := strings.ToUpper(query)
+	if strings.Contains(upperQuery, "IF NOT EXISTS") || strings.Contains(upperQuery, "IF EXISTS") || strings.Contains(upperQuery, "OR REPLACE") {
+		return true
+	}
+	return !nonIdempotentStatementRegexp.MatchString(query)
+}
+
+// retryBackoff returns the delay before the given attempt (starting at 1): an exponential backoff
+// with jitter, so that concurrent resources don't hammer a recovering server at the same time
+func (c *Client) retryBackoff(attempt int) time.Duration {
+	backoff := c.RetryBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// withRetry runs the operation until it succeeds, fails with a fatal error or `MaxRetries` is reached.
+// Non idempotent operations are never retried.
+func (c *Client) withRetry(ctx context.Context, query string, idempotent bool, operation func() error) error {
+	err := operation()
+	for attempt := 1; err != nil && idempotent && attempt <= c.MaxRetries && isRetryableError(err); attempt++ {
+		backoff := c.retryBackoff(attempt)
+		tflog.Warn(ctx, fmt.Sprintf("transient error executing query, retrying %d/%d in %s: %v", attempt, c.MaxRetries, backoff, err), map[string]interface{}{
+			"query": query,
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		err = operation()
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	ok, failed := int64(0), int64(60)
+	testCases := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "nil", err: nil, retryable: false},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), retryable: true},
+		{name: "unexpected EOF", err: io.EOF, retryable: true},
+		{name: "canceled", err: context.Canceled, retryable: false},
+		{name: "distributed DDL timeout", err: &clickhouse.Exception{Code: 159, Message: "Watching task is executing longer than distributed_ddl_task_timeout"}, retryable: true},
+		{name: "distributed DDL unfinished", err: &DistributedDDLError{Hosts: []DistributedDDLHost{{Host: "ch1", Port: 9000, Status: &ok}, {Host: "ch2", Port: 9000}}}, retryable: true},
+		{name: "distributed DDL failed", err: &DistributedDDLError{Hosts: []DistributedDDLHost{{Host: "ch1", Port: 9000, Status: &failed}, {Host: "ch2", Port: 9000}}}, retryable: false},
+		{name: "read only replica", err: fmt.Errorf("wrapped: %w", &clickhouse.Exception{Code: 242}), retryable: true},
+		{name: "syntax error", err: &clickhouse.Exception{Code: 62, Message: "Syntax error"}, retryable: false},
+		{name: "http timeout", err: errors.New("clickhouse [execute]:: 500 code: Code: 159. DB::Exception: Timeout exceeded"), retryable: true},
+		{name: "http unknown table", err: errors.New("clickhouse [execute]:: 404 code: Code: 60. DB::Exception: Table default.t does not exist"), retryable: false},
+		{name: "other", err: errors.New("boom"), retryable: false},
+	}
+
+	for _, tt := range testCases {
+		if retryable := isRetryableError(tt.err); retryable != tt.retryable {
+			t.Errorf("%s: isRetryableError() = %t, expected %t", tt.name, retryable, tt.retryable)
+		}
+	}
+}
+
+func TestIsIdempotentStatement(t *testing.T) {
+	testCases := []struct {
+		query      string
+		idempotent bool
+	}{
+		{query: "CREATE TABLE db.t (a Int32) ENGINE = Memory", idempotent: false},
+		{query: "CREATE TABLE IF NOT EXISTS db.t (a Int32) ENGINE = Memory", idempotent: true},
+		{query: "CREATE OR REPLACE VIEW db.v AS SELECT 1", idempotent: true},
+		{query: "DROP TABLE db.t", idempotent: false},
+		{query: "DROP TABLE IF EXISTS db.t", idempotent: true},
+		{query: "ALTER TABLE db.t ADD COLUMN b Int32", idempotent: false},
+		{query: "ALTER TABLE db.t MODIFY COLUMN b Int64", idempotent: true},
+		{query: "ALTER TABLE db.t MODIFY COMMENT 'comment'", idempotent: true},
+		{query: "ALTER ROLE reader RENAME TO writer", idempotent: false},
+		{query: "GRANT SELECT ON db.* TO reader", idempotent: true},
+		{query: "SELECT name FROM system.databases", idempotent: true},
+	}
+
+	for _, tt := range testCases {
+		if idempotent := isIdempotentStatement(tt.query); idempotent != tt.idempotent {
+			t.Errorf("isIdempotentStatement(%q) = %t, expected %t", tt.query, idempotent, tt.idempotent)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	transientErr := &clickhouse.Exception{Code: 209}
+	fatalErr := &clickhouse.Exception{Code: 62}
+
+	testCases := []struct {
+		name             string
+		idempotent       bool
+		errors           []error
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{name: "success", idempotent: true, errors: []error{nil}, expectedAttempts: 1},
+		{name: "recovers", idempotent: true, errors: []error{transientErr, transientErr, nil}, expectedAttempts: 3},
+		{name: "gives up", idempotent: true, errors: []error{transientErr, transientErr, transientErr, transientErr}, expectedErr: transientErr, expectedAttempts: 3},
+		{name: "fatal", idempotent: true, errors: []error{fatalErr, nil}, expectedErr: fatalErr, expectedAttempts: 1},
+		{name: "not idempotent", idempotent: false, errors: []error{transientErr, nil}, expectedErr: transientErr, expectedAttempts: 1},
+	}
+
+	client := &Client{MaxRetries: 2, RetryBackoff: time.Millisecond}
+	for _, tt := range testCases {
+		attempts := 0
+		err := client.withRetry(context.Background(), "SELECT 1", tt.idempotent, func() error {
+			err := tt.errors[attempts]
+			attempts++
+			return err
+		})
+		if !errors.Is(err, tt.expectedErr) {
+			t.Errorf("%s: withRetry() error = %v, expected %v", tt.name, err, tt.expectedErr)
+		}
+		if attempts != tt.expectedAttempts {
+			t.Errorf("%s: withRetry() made %d attempts, expected %d", tt.name, attempts, tt.expectedAttempts)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	client := &Client{RetryBackoff: time.Second}
+	for attempt, maxBackoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		backoff := client.retryBackoff(attempt + 1)
+		if backoff < maxBackoff/2 || backoff > maxBackoff {
+			t.Errorf("retryBackoff(%d) = %s, expected between %s and %s", attempt+1, backoff, maxBackoff/2, maxBackoff)
+		}
+	}
+	if backoff := client.retryBackoff(20); backoff > maxRetryBackoff {
+		t.Errorf("retryBackoff(20) = %s, expected at most %s", backoff, maxRetryBackoff)
+	}
+}
@@ -5,20 +5,32 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func getGrantQuery(roleName string, privileges []string, database string) string {
+func getGrantQuery(roleName string, cluster string, privileges []string, database string) string {
+	statement := common.NewStatement("GRANT").OnCluster(cluster)
 	if database == "system" || database == "*" {
-		return fmt.Sprintf("GRANT CURRENT GRANTS (%s ON %s.*) TO %s", strings.Join(privileges, ","), database, roleName)
+		statement.Raw(fmt.Sprintf("CURRENT GRANTS (%s ON %s)", strings.Join(privileges, ","), grantTarget(database)))
+	} else {
+		statement.Raw(strings.Join(privileges, ","), "ON", grantTarget(database))
 	}
-	return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(privileges, ","), database, roleName)
+	return statement.Raw("TO").Identifier(roleName).SQL()
+}
+
+// grantTarget returns the tables of the database the privileges apply to, `*` standing for every database
+func grantTarget(database string) string {
+	if database == "*" {
+		return "*.*"
+	}
+	return common.QuoteIdentifier(database) + ".*"
 }
 
 func (c *Client) getRoleGrants(ctx context.Context, roleName string) ([]models.CHGrant, error) {
-	query := fmt.Sprintf("SELECT role_name, access_type, database FROM system.grants WHERE role_name = '%s'", roleName)
-	rows, err := c.Conn.Query(ctx, query)
+	ctx = WithParameters(ctx, map[string]string{"role_name": roleName})
+	rows, err := c.Query(ctx, "SELECT role_name, access_type, database FROM system.grants WHERE role_name = {role_name:String}")
 
 	if err != nil {
 		return nil, fmt.Errorf("error fetching role grants: %s", err)
@@ -41,9 +53,10 @@ func (c *Client) getRoleGrants(ctx context.Context, roleName string) ([]models.C
 }
 
 func (c *Client) GetRole(ctx context.Context, roleName string) (*models.CHRole, error) {
-	roleQuery := fmt.Sprintf("SELECT name FROM system.roles WHERE name = '%s'", roleName)
-
-	rows, err := c.Conn.Query(ctx, roleQuery)
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"name": roleName}),
+		"SELECT name FROM system.roles WHERE name = {name:String}",
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching role: %s", err)
 	}
@@ -73,6 +86,8 @@ func (c *Client) UpdateRole(ctx context.Context, rolePlan models.RoleResource, r
 		return nil, fmt.Errorf("role %s not found", rolePlan.Name)
 	}
 
+	cluster := c.GetCluster(rolePlan.Cluster)
+
 	roleNameHasChange := resourceData.HasChange("name")
 	roleDatabaseHasChange := resourceData.HasChange("database")
 	rolePrivilegesHasChange := resourceData.HasChange("privileges")
@@ -100,20 +115,21 @@ func (c *Client) UpdateRole(ctx context.Context, rolePlan models.RoleResource, r
 	}
 
 	if roleNameHasChange {
-		err := c.Conn.Exec(ctx, fmt.Sprintf("ALTER ROLE %s RENAME TO %s", chRole.Name, rolePlan.Name))
+		err := c.Exec(ctx, common.NewStatement("ALTER ROLE").Identifier(chRole.Name).OnCluster(cluster).Raw("RENAME TO").Identifier(rolePlan.Name).SQL())
 		if err != nil {
 			return nil, fmt.Errorf("error renaming role %s to %s: %v", chRole.Name, rolePlan.Name, err)
 		}
 	}
 
 	if roleDatabaseHasChange {
-		err := c.Conn.Exec(ctx, fmt.Sprintf("REVOKE ALL ON *.* FROM %s", rolePlan.Name))
+		err := c.Exec(ctx, common.NewStatement("REVOKE").OnCluster(cluster).Raw("ALL ON *.* FROM").Identifier(rolePlan.Name).SQL())
 		if err != nil {
 			return nil, fmt.Errorf("error revoking all privileges from role %s: %v", chRole.Name, err)
 		}
 		dbPrivileges := chRole.GetPrivilegesList()
-		err = c.Conn.Exec(ctx, getGrantQuery(
+		err = c.Exec(ctx, getGrantQuery(
 			rolePlan.Name,
+			cluster,
 			dbPrivileges,
 			rolePlan.Database,
 		))
@@ -123,24 +139,33 @@ func (c *Client) UpdateRole(ctx context.Context, rolePlan models.RoleResource, r
 	}
 
 	if len(grantPrivileges) > 0 {
-		err := c.Conn.Exec(ctx, getGrantQuery(rolePlan.Name, grantPrivileges, rolePlan.Database))
+		err := c.Exec(ctx, getGrantQuery(rolePlan.Name, cluster, grantPrivileges, rolePlan.Database))
 		if err != nil {
 			return nil, fmt.Errorf("error granting privileges to role %s: %v", chRole.Name, err)
 		}
 	}
 
 	if len(revokePrivileges) > 0 {
-		err := c.Conn.Exec(ctx, fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(revokePrivileges, ","), rolePlan.Database, rolePlan.Name))
+		err := c.Exec(ctx, common.NewStatement("REVOKE").OnCluster(cluster).Raw(strings.Join(revokePrivileges, ","), "ON", grantTarget(rolePlan.Database), "FROM").Identifier(rolePlan.Name).SQL())
 		if err != nil {
 			return nil, fmt.Errorf("error revoking privileges from role %s: %v", chRole.Name, err)
 		}
 	}
 
+	if c.DryRun {
+		var privileges []models.CHGrant
+		for _, privilege := range common.StringSetToList(rolePlan.Privileges) {
+			privileges = append(privileges, models.CHGrant{RoleName: rolePlan.Name, AccessType: privilege, Database: rolePlan.Database})
+		}
+		return &models.CHRole{Name: rolePlan.Name, Privileges: privileges}, nil
+	}
 	return c.GetRole(ctx, rolePlan.Name)
 }
 
-func (c *Client) CreateRole(ctx context.Context, name string, database string, privileges []string) (*models.CHRole, error) {
-	err := c.Conn.Exec(ctx, fmt.Sprintf("CREATE ROLE %s", name))
+func (c *Client) CreateRole(ctx context.Context, name string, cluster string, database string, privileges []string) (*models.CHRole, error) {
+	cluster = c.GetCluster(cluster)
+
+	err := c.Exec(ctx, common.NewStatement("CREATE ROLE").Identifier(name).OnCluster(cluster).SQL())
 	if err != nil {
 		return nil, fmt.Errorf("error creating role: %s", err)
 	}
@@ -148,10 +173,10 @@ func (c *Client) CreateRole(ctx context.Context, name string, database string, p
 	var chPrivileges []models.CHGrant
 
 	for _, privilege := range privileges {
-		err = c.Conn.Exec(ctx, getGrantQuery(name, []string{privilege}, database))
+		err = c.Exec(ctx, getGrantQuery(name, cluster, []string{privilege}, database))
 		if err != nil {
 			// Rollback
-			err2 := c.Conn.Exec(ctx, fmt.Sprintf("DROP ROLE %s", name))
+			err2 := c.Exec(ctx, common.NewStatement("DROP ROLE").Identifier(name).OnCluster(cluster).SQL())
 			if err2 != nil {
 				return nil, fmt.Errorf("error creating role: %s:%s", err, err2)
 			}
@@ -162,6 +187,6 @@ func (c *Client) CreateRole(ctx context.Context, name string, database string, p
 	return &models.CHRole{Name: name, Privileges: chPrivileges}, nil
 }
 
-func (c *Client) DeleteRole(ctx context.Context, name string) error {
-	return c.Conn.Exec(ctx, fmt.Sprintf("DROP ROLE %s", name))
+func (c *Client) DeleteRole(ctx context.Context, name string, cluster string) error {
+	return c.Exec(ctx, common.NewStatement("DROP ROLE").Identifier(name).OnCluster(c.GetCluster(cluster)).SQL())
 }
@@ -0,0 +1,56 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetGrantQuery(t *testing.T) {
+	testCases := []struct {
+		cluster     string
+		database    string
+		privileges  []string
+		expectedSQL string
+	}{
+		{
+			database:    "analytics",
+			privileges:  []string{"SELECT", "INSERT"},
+			expectedSQL: "GRANT SELECT,INSERT ON `analytics`.* TO `reader`",
+		},
+		{
+			cluster:     "main",
+			database:    "analytics",
+			privileges:  []string{"SELECT"},
+			expectedSQL: "GRANT ON CLUSTER `main` SELECT ON `analytics`.* TO `reader`",
+		},
+		{
+			cluster:     "main",
+			database:    "*",
+			privileges:  []string{"REMOTE"},
+			expectedSQL: "GRANT ON CLUSTER `main` CURRENT GRANTS (REMOTE ON *.*) TO `reader`",
+		},
+		{
+			cluster:     "'{cluster}'",
+			database:    "system",
+			privileges:  []string{"SELECT"},
+			expectedSQL: "GRANT ON CLUSTER '{cluster}' CURRENT GRANTS (SELECT ON `system`.*) TO `reader`",
+		},
+	}
+
+	for _, tt := range testCases {
+		query := strings.Join(strings.Fields(getGrantQuery("reader", tt.cluster, tt.privileges, tt.database)), " ")
+		if query != tt.expectedSQL {
+			t.Errorf("getGrantQuery() = %q, expected %q", query, tt.expectedSQL)
+		}
+	}
+}
+
+func TestGetCluster(t *testing.T) {
+	c := &Client{DefaultCluster: "default_cluster"}
+	if cluster := c.GetCluster(""); cluster != "default_cluster" {
+		t.Errorf("expected the default cluster, got %q", cluster)
+	}
+	if cluster := c.GetCluster("other"); cluster != "other" {
+		t.Errorf("expected the given cluster, got %q", cluster)
+	}
+}
@@ -0,0 +1,384 @@
+// Package sdktest provides an in-memory Clickhouse connection to unit test the statements run by
+// the sdk package and the resources without a server.
+package sdktest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// FakeConn is a driver.Conn recording the statements it executes and answering the queries with
+// canned rows. Queries without canned rows return no rows.
+type FakeConn struct {
+	mu         sync.Mutex
+	statements []string
+	queries    []string
+	contexts   []context.Context
+	results    []cannedResult
+	failures   []cannedFailure
+	// openRows counts the rows returned by Query which aren't closed yet
+	openRows int
+	settings SettingsFunc
+}
+
+// SettingsFunc returns the settings a statement is sent with
+type SettingsFunc func(ctx context.Context, query string) clickhouse.Settings
+
+type cannedResult struct {
+	pattern *regexp.Regexp
+	columns []string
+	rows    [][]any
+	// once results only answer the first matching query
+	once bool
+	used bool
+}
+
+type cannedFailure struct {
+	pattern *regexp.Regexp
+	err     error
+}
+
+var _ driver.Conn = &FakeConn{}
+
+// readQueryRegexp matches the queries which don't modify Clickhouse
+var readQueryRegexp = regexp.MustCompile(`(?i)^\s*(SELECT|WITH|SHOW|DESCRIBE|DESC|EXISTS|EXPLAIN)\b`)
+
+func NewFakeConn() *FakeConn {
+	return &FakeConn{}
+}
+
+// AddRows answers the queries matching the pattern with the rows, whose values are listed in the
+// order of the columns. The first matching pattern wins.
+func (c *FakeConn) AddRows(pattern string, columns []string, rows ...[]any) *FakeConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, cannedResult{pattern: regexp.MustCompile(pattern), columns: columns, rows: rows})
+	return c
+}
+
+// AddRowsOnce is AddRows for a single query, the next queries matching the pattern are answered by
+// the following patterns. It lets a test return different rows to the same query over time.
+func (c *FakeConn) AddRowsOnce(pattern string, columns []string, rows ...[]any) *FakeConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, cannedResult{pattern: regexp.MustCompile(pattern), columns: columns, rows: rows, once: true})
+	return c
+}
+
+// FailOn makes the statements and queries matching the pattern fail with the error
+func (c *FakeConn) FailOn(pattern string, err error) *FakeConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = append(c.failures, cannedFailure{pattern: regexp.MustCompile(pattern), err: err})
+	return c
+}
+
+// WithSettings reads the settings of the statements with the function, e.g. the querySettings of
+// the client. Like the server, the fake fails the ON CLUSTER statements whose canned rows have
+// failed or unfinished hosts according to their `distributed_ddl_output_mode`, which defaults to
+// `throw` without settings.
+func (c *FakeConn) WithSettings(settings SettingsFunc) *FakeConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings = settings
+	return c
+}
+
+// Statements returns the statements passed to Exec, and to Query for the ones modifying Clickhouse
+// (e.g. ON CLUSTER statements, which return the result of every host), in order
+func (c *FakeConn) Statements() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.statements...)
+}
+
+// Queries returns the queries passed to Query and QueryRow, in order
+func (c *FakeConn) Queries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+// Contexts returns the contexts passed to Query and QueryRow, in the order of Queries. They carry
+// the settings and parameters of the queries.
+func (c *FakeConn) Contexts() []context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]context.Context(nil), c.contexts...)
+}
+
+// OpenRows returns the number of rows returned by Query which weren't closed
+func (c *FakeConn) OpenRows() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.openRows
+}
+
+func (c *FakeConn) failure(query string) error {
+	for _, failure := range c.failures {
+		if failure.pattern.MatchString(query) {
+			return failure.err
+		}
+	}
+	return nil
+}
+
+func (c *FakeConn) Exec(_ context.Context, query string, _ ...any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements = append(c.statements, strings.TrimSpace(query))
+	return c.failure(query)
+}
+
+func (c *FakeConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.openRows++
+	rows.conn = c
+	return rows, nil
+}
+
+func (c *FakeConn) query(ctx context.Context, query string, _ ...any) (*fakeRows, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, strings.TrimSpace(query))
+	c.contexts = append(c.contexts, ctx)
+	if !readQueryRegexp.MatchString(query) {
+		c.statements = append(c.statements, strings.TrimSpace(query))
+	}
+	if err := c.failure(query); err != nil {
+		return nil, err
+	}
+	for i := range c.results {
+		result := &c.results[i]
+		if !result.used && result.pattern.MatchString(query) {
+			result.used = result.once
+			if !readQueryRegexp.MatchString(query) {
+				if err := distributedDDLFailure(c.outputMode(ctx, query), result.columns, result.rows); err != nil {
+					return nil, err
+				}
+			}
+			return &fakeRows{columns: result.columns, rows: result.rows, index: -1}, nil
+		}
+	}
+	return &fakeRows{index: -1}, nil
+}
+
+// outputMode returns the `distributed_ddl_output_mode` of the statement
+func (c *FakeConn) outputMode(ctx context.Context, query string) string {
+	if c.settings != nil {
+		if mode, ok := c.settings(ctx, query)["distributed_ddl_output_mode"]; ok {
+			return fmt.Sprint(mode)
+		}
+	}
+	return "throw"
+}
+
+// distributedDDLFailure returns the exception the server throws instead of the rows of an ON CLUSTER
+// statement: a timeout when some hosts didn't finish and the mode doesn't return them with a NULL
+// status, else the error of the first failing host unless the mode is `never_throw`
+func distributedDDLFailure(mode string, columns []string, rows [][]any) error {
+	index := map[string]int{}
+	for i, column := range columns {
+		index[column] = i
+	}
+	statusIndex, ok := index["status"]
+	if !ok {
+		return nil
+	}
+
+	var failed []any
+	unfinished := 0
+	for _, row := range rows {
+		switch status := row[statusIndex]; {
+		case status == nil:
+			unfinished++
+		case failed == nil && fmt.Sprint(status) != "0":
+			failed = row
+		}
+	}
+
+	switch mode = strings.TrimSuffix(mode, "_only_active"); {
+	case unfinished > 0 && (mode == "throw" || mode == "none"):
+		return &clickhouse.Exception{
+			Code:    159,
+			Name:    "DB::Exception",
+			Message: fmt.Sprintf("Distributed DDL task is not finished on %d of %d hosts", unfinished, len(rows)),
+		}
+	case failed != nil && mode != "never_throw":
+		return &clickhouse.Exception{
+			Code:    int32(reflect.ValueOf(failed[statusIndex]).Int()),
+			Name:    "DB::Exception",
+			Message: fmt.Sprintf("There was an error on [%v:%v]: %v", failed[index["host"]], failed[index["port"]], failed[index["error"]]),
+		}
+	}
+	return nil
+}
+
+func (c *FakeConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return &fakeRow{err: err}
+	}
+	return &fakeRow{rows: rows}
+}
+
+func (c *FakeConn) Select(ctx context.Context, dest any, query string, args ...any) error {
+	return fmt.Errorf("sdktest: Select is not supported")
+}
+
+func (c *FakeConn) PrepareBatch(context.Context, string, ...driver.PrepareBatchOption) (driver.Batch, error) {
+	return nil, fmt.Errorf("sdktest: PrepareBatch is not supported")
+}
+
+func (c *FakeConn) AsyncInsert(context.Context, string, bool, ...any) error {
+	return fmt.Errorf("sdktest: AsyncInsert is not supported")
+}
+
+func (c *FakeConn) Contributors() []string { return nil }
+func (c *FakeConn) ServerVersion() (*driver.ServerVersion, error) {
+	return &driver.ServerVersion{}, nil
+}
+func (c *FakeConn) Ping(context.Context) error { return nil }
+func (c *FakeConn) Stats() driver.Stats        { return driver.Stats{} }
+func (c *FakeConn) Close() error               { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]any
+	index   int
+	// conn is the connection counting the open rows, nil for the rows of QueryRow
+	conn   *FakeConn
+	closed bool
+}
+
+func (r *fakeRows) Next() bool {
+	r.index++
+	return r.index < len(r.rows)
+}
+
+// Scan assigns the values of the current row to the destinations, in the order of the columns
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.rows[r.index]
+	if len(dest) != len(row) {
+		return fmt.Errorf("sdktest: %d destinations for %d columns", len(dest), len(row))
+	}
+	for i, value := range row {
+		if err := assign(dest[i], value); err != nil {
+			return fmt.Errorf("sdktest: column %s: %v", r.columns[i], err)
+		}
+	}
+	return nil
+}
+
+// ScanStruct assigns the values of the current row to the fields with the matching `ch` tag
+func (r *fakeRows) ScanStruct(dest any) error {
+	structValue := reflect.ValueOf(dest).Elem()
+	fields := map[string]reflect.Value{}
+	for i := 0; i < structValue.NumField(); i++ {
+		if tag := structValue.Type().Field(i).Tag.Get("ch"); tag != "" {
+			fields[tag] = structValue.Field(i)
+		}
+	}
+
+	for i, value := range r.rows[r.index] {
+		field, ok := fields[r.columns[i]]
+		if !ok {
+			return fmt.Errorf("sdktest: missing destination name %q in %T", r.columns[i], dest)
+		}
+		if err := assign(field.Addr().Interface(), value); err != nil {
+			return fmt.Errorf("sdktest: column %s: %v", r.columns[i], err)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Columns() []string                { return r.columns }
+func (r *fakeRows) ColumnTypes() []driver.ColumnType { return nil }
+func (r *fakeRows) Totals(...any) error              { return nil }
+
+func (r *fakeRows) Err() error { return nil }
+
+func (r *fakeRows) Close() error {
+	if r.conn != nil && !r.closed {
+		r.conn.mu.Lock()
+		defer r.conn.mu.Unlock()
+		r.conn.openRows--
+	}
+	r.closed = true
+	return nil
+}
+
+type fakeRow struct {
+	err  error
+	rows *fakeRows
+}
+
+func (r *fakeRow) Err() error {
+	return r.err
+}
+
+func (r *fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.rows.Next() {
+		return sql.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+func (r *fakeRow) ScanStruct(dest any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.rows.Next() {
+		return sql.ErrNoRows
+	}
+	return r.rows.ScanStruct(dest)
+}
+
+// assign stores the value in the destination pointer, converting it when the types differ
+func assign(dest any, value any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	target := destValue.Elem()
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if target.Kind() == reflect.Pointer && v.Kind() != reflect.Pointer {
+		// e.g. a Nullable column scanned in a **T
+		pointer := reflect.New(target.Type().Elem())
+		if err := assign(pointer.Interface(), value); err != nil {
+			return err
+		}
+		target.Set(pointer)
+		return nil
+	}
+	switch {
+	case v.Type().AssignableTo(target.Type()):
+		target.Set(v)
+	case v.Type().ConvertibleTo(target.Type()) && v.Kind() != reflect.String && target.Kind() != reflect.String:
+		target.Set(v.Convert(target.Type()))
+	default:
+		return fmt.Errorf("can't assign %T to %s", value, target.Type())
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package sdktest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files with the statements executed by the tests")
+
+// AssertGolden compares the statements with the golden file, where each statement is terminated by
+// a semicolon and a new line like in the dry run `sql_output_file`. Run the tests with -update to
+// write the golden files.
+func AssertGolden(t *testing.T, path string, statements []string) {
+	t.Helper()
+
+	var content strings.Builder
+	for _, statement := range statements {
+		content.WriteString(statement + ";\n")
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating the golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content.String()), 0o644); err != nil {
+			t.Fatalf("writing the golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading the golden file, run the test with -update to create it: %v", err)
+	}
+	if content.String() != string(expected) {
+		t.Errorf("statements differ from %s, run the test with -update to update it\ngot:\n%s\nexpected:\n%s", path, content.String(), expected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
@@ -11,10 +12,15 @@ import (
 )
 
 func (c *Client) UpdateTable(ctx context.Context, table models.TableResource, resourceData *schema.ResourceData) error {
-	clusterStatement := common.GetClusterStatement(table.Cluster)
+	cluster := c.GetCluster(table.Cluster)
 
 	if resourceData.HasChange("comment") {
-		query := fmt.Sprintf("ALTER TABLE %s.%s %s MODIFY COMMENT '%s'", table.Database, table.Name, clusterStatement, table.Comment)
+		query := common.NewStatement("ALTER TABLE").
+			QualifiedName(table.Database, table.Name).
+			OnCluster(cluster).
+			Raw("MODIFY COMMENT").
+			Literal(table.Comment).
+			SQL()
 		err := executeQuery(ctx, c, query)
 		if err != nil {
 			return err
@@ -25,7 +31,7 @@ func (c *Client) UpdateTable(ctx context.Context, table models.TableResource, re
 		_, new := resourceData.GetChange("ttl")
 		newTTL := new.(map[string]interface{})
 
-		err := UpdateTTL(ctx, c, table, clusterStatement, newTTL)
+		err := UpdateTTL(ctx, c, table, cluster, newTTL)
 		if err != nil {
 			return err
 		}
@@ -46,15 +52,15 @@ func (c *Client) UpdateTable(ctx context.Context, table models.TableResource, re
 
 			columnName := columnMap["name"].(string)
 
-			err := UpdateColumns(ctx, c, table, clusterStatement, columnMap, oldColumnsMap)
+			err := UpdateColumns(ctx, c, table, cluster, columnMap, oldColumnsMap)
 			if err != nil {
 				return err
 			}
 
-			location = "AFTER " + columnName
+			location = "AFTER " + common.QuoteIdentifier(columnName)
 		}
 
-		err := dropOldColumns(ctx, c, table, clusterStatement, oldColumns, newColumnsMap)
+		err := dropOldColumns(ctx, c, table, cluster, oldColumns, newColumnsMap)
 		if err != nil {
 			return err
 		}
@@ -63,8 +69,10 @@ func (c *Client) UpdateTable(ctx context.Context, table models.TableResource, re
 }
 
 func (c *Client) GetTable(ctx context.Context, database string, table string) (*models.CHTable, error) {
-	query := fmt.Sprintf("SELECT database, name, engine_full, engine, sorting_key, comment FROM system.tables where database = '%s' and name = '%s'", database, table)
-	row := c.Conn.QueryRow(ctx, query)
+	row := c.QueryRow(
+		WithParameters(ctx, map[string]string{"database": database, "name": table}),
+		"SELECT database, name, engine_full, engine, sorting_key, partition_key, primary_key, comment FROM system.tables WHERE database = {database:String} AND name = {name:String}",
+	)
 
 	if row.Err() != nil {
 		return nil, fmt.Errorf("reading table from Clickhouse: %v", row.Err())
@@ -93,11 +101,27 @@ func (c *Client) GetTable(ctx context.Context, database string, table string) (*
 }
 
 func (c *Client) CreateTable(ctx context.Context, tableResource models.TableResource) error {
+	tableResource.Cluster = c.GetCluster(tableResource.Cluster)
+	if tableResource.CreateMode == common.CreateModeAdopt {
+		existing, err := c.GetTable(ctx, tableResource.Database, tableResource.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if differences := tableResource.Differences(existing); len(differences) > 0 {
+				return fmt.Errorf("table %s.%s already exists and differs from the configuration: %s", tableResource.Database, tableResource.Name, strings.Join(differences, ", "))
+			}
+			return nil
+		}
+	}
 	query := buildCreateTableOnClusterSentence(tableResource)
 	return executeQuery(ctx, c, query)
 }
 
 func (c *Client) DeleteTable(ctx context.Context, tableResource models.TableResource) error {
-	query := fmt.Sprintf("DROP TABLE IF EXISTS %s.%s %s", tableResource.Database, tableResource.Name, common.GetClusterStatement(tableResource.Cluster))
+	query := common.NewStatement("DROP TABLE IF EXISTS").
+		QualifiedName(tableResource.Database, tableResource.Name).
+		OnCluster(c.GetCluster(tableResource.Cluster)).
+		SQL()
 	return executeQuery(ctx, c, query)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,12 +26,10 @@ func (c *Client) GetColumnDefintions(columns []models.ColumnDefinition) []map[st
 }
 
 func (c *Client) getColumns(ctx context.Context, database string, table string) ([]models.CHColumn, error) {
-	query := fmt.Sprintf(
-		"SELECT database, table, name, type, comment, default_kind, default_expression, compression_codec FROM system.columns WHERE database = '%s' AND table = '%s'",
-		database,
-		table,
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"database": database, "table": table}),
+		"SELECT database, table, name, type, comment, default_kind, default_expression, compression_codec FROM system.columns WHERE database = {database:String} AND table = {table:String}",
 	)
-	rows, err := c.Conn.Query(ctx, query)
 
 	if err != nil {
 		return nil, fmt.Errorf("reading columns from Clickhouse: %v", err)
@@ -58,7 +57,7 @@ func copyToMap(iface interface{}) map[string]interface{} {
 }
 
 func executeQuery(ctx context.Context, c *Client, query string) error {
-	err := c.Conn.Exec(ctx, query)
+	err := c.Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("executing query: %v", err)
 	}
@@ -75,48 +74,50 @@ func createColumnsMap(columns []interface{}) map[string]map[string]interface{} {
 	return columnsMap
 }
 
-func UpdateColumns(ctx context.Context, c *Client, table models.TableResource, clusterStatement string, columnMap map[string]interface{}, oldColumnsMap map[string]map[string]interface{}) error {
+func UpdateColumns(ctx context.Context, c *Client, table models.TableResource, cluster string, columnMap map[string]interface{}, oldColumnsMap map[string]map[string]interface{}) error {
 	columnName := columnMap["name"].(string)
 	oldColumnMap, exists := oldColumnsMap[columnName]
 
-	generateArgs := func(extraArgs ...interface{}) []interface{} {
-		return append([]interface{}{table.Database, table.Name, clusterStatement, columnName}, extraArgs...)
+	alterColumn := func(action string) *common.Statement {
+		return common.NewStatement("ALTER TABLE").
+			QualifiedName(table.Database, table.Name).
+			OnCluster(cluster).
+			Raw(action).
+			Identifier(columnName)
 	}
 
 	changes := []struct {
 		condition bool
-		query     string
-		args      []interface{}
+		statement *common.Statement
 	}{
 		{
 			condition: !exists,
-			query:     "ALTER TABLE %s.%s %s ADD COLUMN %s %s %s %s %s %s %s",
-			args:      generateArgs(columnMap["type"], columnMap["default_kind"], columnMap["default_expression"], columnMap["compression_codec"], columnMap["comment"].(string), columnMap["location"]),
+			statement: alterColumn("ADD COLUMN").
+				Raw(columnMap["type"].(string), columnMap["default_kind"].(string), columnMap["default_expression"].(string), columnMap["compression_codec"].(string)).
+				Comment(columnMap["comment"].(string)).
+				Raw(columnMap["location"].(string)),
 		},
 		{
 			condition: exists && columnDiffers(oldColumnMap, columnMap, "type"),
-			query:     "ALTER TABLE %s.%s %s MODIFY COLUMN %s %s",
-			args:      generateArgs(columnMap["type"]),
+			statement: alterColumn("MODIFY COLUMN").Raw(columnMap["type"].(string)),
 		},
 		{
 			condition: exists && columnDiffers(oldColumnMap, columnMap, "comment"),
-			query:     "ALTER TABLE %s.%s %s COMMENT COLUMN %s '%s'",
-			args:      generateArgs(columnMap["comment"]),
+			statement: alterColumn("COMMENT COLUMN").Literal(columnMap["comment"].(string)),
 		},
 		{
 			condition: exists && columnDiffers(oldColumnMap, columnMap, "default_kind", "default_expression", "compression_codec"),
-			query:     "ALTER TABLE %s.%s %s MODIFY COLUMN %s %s %s %s",
-			args: generateArgs(
-				columnMap["default_kind"],
-				columnMap["default_expression"],
-				columnMap["compression_codec"],
+			statement: alterColumn("MODIFY COLUMN").Raw(
+				columnMap["default_kind"].(string),
+				columnMap["default_expression"].(string),
+				columnMap["compression_codec"].(string),
 			),
 		},
 	}
 
 	for _, change := range changes {
 		if change.condition {
-			query := fmt.Sprintf(change.query, change.args...)
+			query := change.statement.SQL()
 			tflog.Debug(ctx, fmt.Sprintf("Executing query: %s", query))
 
 			if err := executeQuery(ctx, c, query); err != nil {
@@ -136,13 +137,16 @@ func columnDiffers(oldMap, newMap map[string]interface{}, keys ...string) bool {
 	return false
 }
 
-func dropOldColumns(ctx context.Context, c *Client, table models.TableResource, clusterStatement string, oldColumns []interface{}, newColumnsMap map[string]map[string]interface{}) error {
+func dropOldColumns(ctx context.Context, c *Client, table models.TableResource, cluster string, oldColumns []interface{}, newColumnsMap map[string]map[string]interface{}) error {
 	for _, column := range oldColumns {
 		columnMap := column.(map[string]interface{})
 		if _, exists := newColumnsMap[columnMap["name"].(string)]; !exists {
-			err := executeQuery(ctx, c, fmt.Sprintf(
-				"ALTER TABLE %s.%s %s DROP COLUMN %s",
-				table.Database, table.Name, clusterStatement, columnMap["name"]))
+			err := executeQuery(ctx, c, common.NewStatement("ALTER TABLE").
+				QualifiedName(table.Database, table.Name).
+				OnCluster(cluster).
+				Raw("DROP COLUMN").
+				Identifier(columnMap["name"].(string)).
+				SQL())
 			if err != nil {
 				return fmt.Errorf("dropping columns from Clickhouse table: %v", err)
 			}
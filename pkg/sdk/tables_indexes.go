@@ -22,12 +22,10 @@ func (c *Client) GetIndexDefintions(indexes []models.IndexDefinition) []map[stri
 }
 
 func (c *Client) getIndexes(ctx context.Context, database string, table string) ([]models.CHIndex, error) {
-	query := fmt.Sprintf(
-		"SELECT name, expr, type, granularity FROM system.data_skipping_indices WHERE database = '%s' AND table = '%s'",
-		database,
-		table,
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"database": database, "table": table}),
+		"SELECT name, expr, type, granularity FROM system.data_skipping_indices WHERE database = {database:String} AND table = {table:String}",
 	)
-	rows, err := c.Conn.Query(ctx, query)
 
 	if err != nil {
 		return nil, fmt.Errorf("reading indexes from Clickhouse: %v", err)
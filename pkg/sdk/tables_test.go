@@ -0,0 +1,188 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+func TestCreateTableStatements(t *testing.T) {
+	testCases := []struct {
+		name           string
+		defaultCluster string
+		table          models.TableResource
+	}{
+		{
+			name: "merge_tree",
+			table: models.TableResource{
+				Database: "analytics",
+				Name:     "events",
+				Engine:   "MergeTree",
+				Comment:  "Raw events",
+				Columns: []models.ColumnDefinition{
+					{Name: "event_date", Type: "Date"},
+					{Name: "event_type", Type: "Int32", Comment: "Type of the event"},
+					{Name: "payload", Type: "String", DefaultKind: "DEFAULT", DefaultExpression: "''", CompressionCodec: "ZSTD(3)"},
+				},
+				Indexes:     []models.IndexDefinition{{Name: "payload_idx", Expression: "payload", Type: "bloom_filter", Granularity: 4}},
+				PartitionBy: []models.PartitionByResource{{By: "event_date", PartitionFunction: "toYYYYMM"}, {By: "event_type"}},
+				OrderBy:     []string{"event_date", "event_type"},
+				Settings:    map[string]string{"index_granularity": "8192"},
+				TTL:         map[string]string{"event_date + INTERVAL 1 MONTH": "DELETE"},
+			},
+		},
+		{
+			name:           "replicated_on_default_cluster",
+			defaultCluster: "main",
+			table: models.TableResource{
+				Database:     "analytics",
+				Name:         "events",
+				Engine:       "ReplicatedMergeTree",
+				EngineParams: []string{"'/clickhouse/tables/{shard}/analytics/events'", "'{replica}'"},
+				Columns:      []models.ColumnDefinition{{Name: "event_date", Type: "Date"}},
+				OrderBy:      []string{"event_date"},
+				CreateMode:   common.CreateModeIfNotExists,
+			},
+		},
+		{
+			name: "distributed",
+			table: models.TableResource{
+				Database:     "analytics",
+				Name:         "events_distributed",
+				Cluster:      "main",
+				Engine:       "Distributed",
+				EngineParams: []string{"main", "analytics", "events", "rand()"},
+				Columns:      []models.ColumnDefinition{{Name: "event_date", Type: "Date"}},
+			},
+		}, {
+			name: "quoted_names",
+			table: models.TableResource{
+				Database: "web-analytics",
+				Name:     "page.views",
+				Cluster:  "'{cluster}'",
+				Engine:   "MergeTree",
+				Comment:  "Visitor's page views",
+				Columns: []models.ColumnDefinition{
+					{Name: "article.id", Type: "UInt64", Comment: "The article's id"},
+					{Name: "weird`name", Type: "String"},
+				},
+				Indexes:  []models.IndexDefinition{{Name: "article-idx", Expression: "`article.id`", Type: "minmax"}},
+				OrderBy:  []string{"`article.id`"},
+				Settings: map[string]string{"storage_policy": "it's"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := sdktest.NewFakeConn()
+			c := NewClientWithConn(conn)
+			c.DefaultCluster = tc.defaultCluster
+
+			if err := c.CreateTable(context.Background(), tc.table); err != nil {
+				t.Fatalf("CreateTable() error = %v", err)
+			}
+			if err := c.DeleteTable(context.Background(), tc.table); err != nil {
+				t.Fatalf("DeleteTable() error = %v", err)
+			}
+
+			sdktest.AssertGolden(t, filepath.Join("testdata", "create_table_"+tc.name+".sql"), conn.Statements())
+		})
+	}
+}
+
+func TestCreateTableAdopt(t *testing.T) {
+	table := models.TableResource{
+		Database:   "analytics",
+		Name:       "events",
+		Engine:     "MergeTree",
+		Columns:    []models.ColumnDefinition{{Name: "event_date", Type: "Date"}},
+		OrderBy:    []string{"event_date"},
+		CreateMode: common.CreateModeAdopt,
+	}
+
+	existingTable := func(conn *sdktest.FakeConn, comment string) {
+		conn.AddRows(`FROM system\.tables`,
+			[]string{"database", "name", "engine_full", "engine", "sorting_key", "primary_key", "comment"},
+			[]any{"analytics", "events", "MergeTree ORDER BY event_date SETTINGS index_granularity = 8192", "MergeTree", "event_date", "event_date", comment},
+		)
+		conn.AddRows(`FROM system\.columns`,
+			[]string{"database", "table", "name", "type", "comment", "default_kind", "default_expression", "compression_codec"},
+			[]any{"analytics", "events", "event_date", "Date", "", "", "", ""},
+		)
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		conn := sdktest.NewFakeConn()
+		existingTable(conn, "")
+		if err := NewClientWithConn(conn).CreateTable(context.Background(), table); err != nil {
+			t.Fatalf("CreateTable() error = %v", err)
+		}
+		if statements := conn.Statements(); len(statements) != 0 {
+			t.Errorf("expected the table to be adopted, got %v", statements)
+		}
+	})
+
+	t.Run("different", func(t *testing.T) {
+		conn := sdktest.NewFakeConn()
+		existingTable(conn, "Another table")
+		err := NewClientWithConn(conn).CreateTable(context.Background(), table)
+		if err == nil || !strings.Contains(err.Error(), "differs from the configuration") {
+			t.Fatalf("expected the table to differ, got %v", err)
+		}
+		if statements := conn.Statements(); len(statements) != 0 {
+			t.Errorf("expected no statement, got %v", statements)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		conn := sdktest.NewFakeConn()
+		if err := NewClientWithConn(conn).CreateTable(context.Background(), table); err != nil {
+			t.Fatalf("CreateTable() error = %v", err)
+		}
+		if statements := conn.Statements(); len(statements) != 1 || !strings.HasPrefix(statements[0], "CREATE TABLE") {
+			t.Errorf("expected the table to be created, got %v", statements)
+		}
+	})
+}
+
+func TestGetTable(t *testing.T) {
+	conn := sdktest.NewFakeConn().
+		AddRows(`FROM system\.tables`,
+			[]string{"database", "name", "engine_full", "engine", "sorting_key", "comment"},
+			[]any{"analytics", "events", "MergeTree ORDER BY event_date", "MergeTree", "event_date", "Raw events"},
+		).
+		AddRows(`FROM system\.columns`,
+			[]string{"database", "table", "name", "type", "comment", "default_kind", "default_expression", "compression_codec"},
+			[]any{"analytics", "events", "event_date", "Date", "", "", "", ""},
+			[]any{"analytics", "events", "payload", "String", "", "DEFAULT", "''", "CODEC(ZSTD(3))"},
+		).
+		AddRows(`FROM system\.data_skipping_indices`,
+			[]string{"name", "expr", "type", "granularity"},
+			[]any{"payload_idx", "payload", "bloom_filter", uint64(4)},
+		)
+
+	table, err := NewClientWithConn(conn).GetTable(context.Background(), "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+	if table.Comment != "Raw events" || len(table.Columns) != 2 || table.Columns[1].DefaultExpression != "''" || len(table.Indexes) != 1 || table.Indexes[0].Granularity != 4 {
+		t.Errorf("unexpected table %+v", table)
+	}
+
+	for _, query := range conn.Queries() {
+		if strings.Contains(query, "'analytics'") || !strings.Contains(query, "{database:String}") {
+			t.Errorf("expected the database to be a query parameter, got %q", query)
+		}
+	}
+
+	missing, err := NewClientWithConn(sdktest.NewFakeConn()).GetTable(context.Background(), "analytics", "missing")
+	if err != nil || missing != nil {
+		t.Errorf("expected no table, got %+v, %v", missing, err)
+	}
+}
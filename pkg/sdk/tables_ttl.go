@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 )
 
-func UpdateTTL(ctx context.Context, c *Client, table models.TableResource, clusterStatement string, newTTL map[string]interface{}) error {
+func UpdateTTL(ctx context.Context, c *Client, table models.TableResource, cluster string, newTTL map[string]interface{}) error {
 	var ttlExprs []string
 	for k, v := range newTTL {
 		ttlExpr := fmt.Sprintf("%s %s", k, v)
@@ -17,8 +18,11 @@ func UpdateTTL(ctx context.Context, c *Client, table models.TableResource, clust
 
 	ttlExprsStatement := strings.Join(ttlExprs, ", ")
 	if ttlExprsStatement != "" {
-		modifyTTLQuery := fmt.Sprintf("ALTER TABLE %s.%s %s MODIFY TTL %s",
-			table.Database, table.Name, clusterStatement, ttlExprsStatement)
+		modifyTTLQuery := common.NewStatement("ALTER TABLE").
+			QualifiedName(table.Database, table.Name).
+			OnCluster(cluster).
+			Raw("MODIFY TTL", ttlExprsStatement).
+			SQL()
 		err := executeQuery(ctx, c, modifyTTLQuery)
 		if err != nil {
 			return err
@@ -26,8 +30,11 @@ func UpdateTTL(ctx context.Context, c *Client, table models.TableResource, clust
 	}
 
 	if ttlExprsStatement == "" {
-		removeTTLQuery := fmt.Sprintf("ALTER TABLE %s.%s %s (REMOVE TTL)",
-			table.Database, table.Name, clusterStatement)
+		removeTTLQuery := common.NewStatement("ALTER TABLE").
+			QualifiedName(table.Database, table.Name).
+			OnCluster(cluster).
+			Raw("(REMOVE TTL)").
+			SQL()
 		err := executeQuery(ctx, c, removeTTLQuery)
 		if err != nil {
 			return err
@@ -11,7 +11,11 @@ import (
 func buildColumnsSentence(cols []models.ColumnDefinition) []string {
 	outColumn := make([]string, 0)
 	for _, col := range cols {
-		outColumn = append(outColumn, fmt.Sprintf("\t `%s` %s %s %s %s %s", col.Name, col.Type, col.DefaultKind, col.DefaultExpression, col.CompressionCodec, getComment(col.Comment)))
+		column := common.NewStatement().
+			Identifier(col.Name).
+			Raw(col.Type, col.DefaultKind, col.DefaultExpression, col.CompressionCodec).
+			Comment(col.Comment)
+		outColumn = append(outColumn, "\t"+column.SQL())
 	}
 	return outColumn
 }
@@ -19,33 +23,20 @@ func buildColumnsSentence(cols []models.ColumnDefinition) []string {
 func buildIndexesSentence(indexes []models.IndexDefinition) []string {
 	outIndexes := make([]string, 0)
 	for _, index := range indexes {
-		indexStatement := fmt.Sprintf("INDEX %s %s TYPE %s", index.Name, index.Expression, index.Type)
+		indexStatement := common.NewStatement("INDEX").Identifier(index.Name).Raw(index.Expression, "TYPE", index.Type)
 		if index.Granularity > 0 {
-			indexStatement += fmt.Sprintf(" GRANULARITY %d", index.Granularity)
+			indexStatement.Raw(fmt.Sprintf("GRANULARITY %d", index.Granularity))
 		}
-		outIndexes = append(outIndexes, fmt.Sprintf("\t%s", indexStatement))
+		outIndexes = append(outIndexes, "\t"+indexStatement.SQL())
 	}
 	return outIndexes
 }
 
-func getComment(comment string) string {
-	if comment != "" {
-		return fmt.Sprintf("COMMENT '%s'", comment)
-	}
-	return ""
-}
-
 func buildPartitionBySentence(partitionBy []models.PartitionByResource) string {
 	if len(partitionBy) > 0 {
 		partitionBySentenceItems := make([]string, 0)
 		for _, partitionByItem := range partitionBy {
-			if partitionByItem.PartitionFunction == "" {
-				partitionBySentenceItems = append(partitionBySentenceItems, partitionByItem.By)
-			} else if partitionByItem.Mod == "" {
-				partitionBySentenceItems = append(partitionBySentenceItems, fmt.Sprintf("%v(%v)", partitionByItem.PartitionFunction, partitionByItem.By))
-			} else {
-				partitionBySentenceItems = append(partitionBySentenceItems, fmt.Sprintf("%v(%v) %% %v", partitionByItem.PartitionFunction, partitionByItem.By, partitionByItem.Mod))
-			}
+			partitionBySentenceItems = append(partitionBySentenceItems, partitionByItem.Expression())
 		}
 		return fmt.Sprintf("PARTITION BY (%v)", strings.Join(partitionBySentenceItems, ", "))
 	}
@@ -69,7 +60,7 @@ func buildSettingsSentence(settings map[string]string) string {
 	if len(settings) > 0 {
 		settingsList := make([]string, 0)
 		for key, value := range settings {
-			settingsList = append(settingsList, fmt.Sprintf("%s = '%s'", key, value))
+			settingsList = append(settingsList, fmt.Sprintf("%s = %s", key, common.QuoteString(value)))
 		}
 		ret := fmt.Sprintf("SETTINGS %s", strings.Join(settingsList, ", "))
 		return ret
@@ -90,39 +81,25 @@ func buildTTLSentence(ttl map[string]string) string {
 }
 
 func buildCreateTableOnClusterSentence(resource models.TableResource) (query string) {
-	createStatement := common.GetCreateStatement("table")
+	statement := common.NewStatement(common.GetCreateStatement("table", resource.CreateMode)).
+		QualifiedName(resource.Database, resource.Name).
+		OnCluster(resource.Cluster)
 
-	columnsStatement := ""
 	if len(resource.Columns) > 0 {
-		columnsStatement = "("
-		columnsList := buildColumnsSentence(resource.GetColumnsResourceList())
-		columnsStatement += strings.Join(columnsList, ",\n")
-		columnsStatement += ",\n"
-
-		if len(resource.Indexes) > 0 {
-			indexesList := buildIndexesSentence(resource.Indexes)
-			columnsStatement += strings.Join(indexesList, ",\n")
-		}
-		columnsStatement += ")\n"
+		elements := buildColumnsSentence(resource.GetColumnsResourceList())
+		elements = append(elements, buildIndexesSentence(resource.Indexes)...)
+		statement.Raw("(\n" + strings.Join(elements, ",\n") + "\n)")
 	}
 
-	clusterStatement := common.GetClusterStatement(resource.Cluster)
-
-	ret := fmt.Sprintf(
-		"%s %v.%v %v %v ENGINE = %v(%v) %s %s %s %s %s COMMENT '%s'",
-		createStatement,
-		resource.Database,
-		resource.Name,
-		clusterStatement,
-		columnsStatement,
-		resource.Engine,
-		strings.Join(resource.EngineParams, ", "),
-		buildOrderBySentence(resource.OrderBy),
-		buildPrimaryKeySentence(resource.PrimaryKey),
-		buildPartitionBySentence(resource.PartitionBy),
-		buildTTLSentence(resource.TTL),
-		buildSettingsSentence(resource.Settings),
-		resource.Comment,
-	)
-	return ret
+	return statement.
+		Raw(fmt.Sprintf("ENGINE = %v(%v)", resource.Engine, strings.Join(resource.EngineParams, ", "))).
+		Raw(
+			buildOrderBySentence(resource.OrderBy),
+			buildPrimaryKeySentence(resource.PrimaryKey),
+			buildPartitionBySentence(resource.PartitionBy),
+			buildTTLSentence(resource.TTL),
+			buildSettingsSentence(resource.Settings),
+		).
+		Comment(resource.Comment).
+		SQL()
 }
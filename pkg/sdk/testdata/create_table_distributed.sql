@@ -0,0 +1,4 @@
+CREATE TABLE `analytics`.`events_distributed` ON CLUSTER `main` (
+	`event_date` Date
+) ENGINE = Distributed(main, analytics, events, rand());
+DROP TABLE IF EXISTS `analytics`.`events_distributed` ON CLUSTER `main`;
@@ -0,0 +1,7 @@
+CREATE TABLE `analytics`.`events` (
+	`event_date` Date,
+	`event_type` Int32 COMMENT 'Type of the event',
+	`payload` String DEFAULT '' ZSTD(3),
+	INDEX `payload_idx` payload TYPE bloom_filter GRANULARITY 4
+) ENGINE = MergeTree() ORDER BY (event_date, event_type) PARTITION BY (toYYYYMM(event_date), event_type) TTL event_date + INTERVAL 1 MONTH DELETE SETTINGS index_granularity = '8192' COMMENT 'Raw events';
+DROP TABLE IF EXISTS `analytics`.`events`;
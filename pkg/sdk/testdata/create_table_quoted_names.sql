@@ -0,0 +1,6 @@
+CREATE TABLE `web-analytics`.`page.views` ON CLUSTER '{cluster}' (
+	`article.id` UInt64 COMMENT 'The article\'s id',
+	`weird\`name` String,
+	INDEX `article-idx` `article.id` TYPE minmax
+) ENGINE = MergeTree() ORDER BY (`article.id`) SETTINGS storage_policy = 'it\'s' COMMENT 'Visitor\'s page views';
+DROP TABLE IF EXISTS `web-analytics`.`page.views` ON CLUSTER '{cluster}';
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS `analytics`.`events` ON CLUSTER `main` (
+	`event_date` Date
+) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/analytics/events', '{replica}') ORDER BY (event_date);
+DROP TABLE IF EXISTS `analytics`.`events` ON CLUSTER `main`;
@@ -0,0 +1,2 @@
+CREATE MATERIALIZED VIEW `analytics`.`daily_events_mv` ON CLUSTER `main` TO analytics.daily_events AS (SELECT event_date, count() AS events FROM analytics.events GROUP BY event_date);
+DROP VIEW IF EXISTS `analytics`.`daily_events_mv` ON CLUSTER `main`;
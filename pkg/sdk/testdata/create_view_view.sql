@@ -0,0 +1,2 @@
+CREATE VIEW `analytics`.`daily_events` AS (SELECT event_date, count() FROM analytics.events GROUP BY event_date) COMMENT 'Events per day';
+DROP VIEW IF EXISTS `analytics`.`daily_events`;
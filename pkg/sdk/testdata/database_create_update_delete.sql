@@ -0,0 +1,3 @@
+CREATE DATABASE `analytics` ON CLUSTER `main` ENGINE = Replicated COMMENT 'Raw events';
+ALTER DATABASE `analytics` ON CLUSTER `main` MODIFY COMMENT 'Events';
+DROP DATABASE `analytics` ON CLUSTER `main` SYNC;
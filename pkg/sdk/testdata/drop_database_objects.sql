@@ -0,0 +1,7 @@
+DROP DICTIONARY IF EXISTS `analytics`.`users_dict` ON CLUSTER `main` SYNC;
+DROP VIEW IF EXISTS `analytics`.`z_mv` ON CLUSTER `main` SYNC;
+DROP VIEW IF EXISTS `analytics`.`events_mv` ON CLUSTER `main` SYNC;
+DROP VIEW IF EXISTS `analytics`.`daily` ON CLUSTER `main` SYNC;
+DROP TABLE IF EXISTS `analytics`.`events` ON CLUSTER `main` SYNC;
+DROP TABLE IF EXISTS `analytics`.`totals` ON CLUSTER `main` SYNC;
+DROP TABLE IF EXISTS `analytics`.`users` ON CLUSTER `main` SYNC;
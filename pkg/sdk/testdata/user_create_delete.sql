@@ -0,0 +1,2 @@
+CREATE USER `etl-reader` ON CLUSTER `main` IDENTIFIED WITH sha256_password BY 'it\'s a \\secret' DEFAULT ROLE `analytics-reader`;
+DROP USER `etl-reader` ON CLUSTER `main`;
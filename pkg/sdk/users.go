@@ -3,7 +3,6 @@ package sdk
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
@@ -11,9 +10,10 @@ import (
 )
 
 func (c *Client) GetUser(ctx context.Context, userName string) (*models.CHUser, error) {
-	roleQuery := fmt.Sprintf("SELECT name, default_roles_list FROM system.users WHERE name = '%s'", userName)
-
-	rows, err := c.Conn.Query(ctx, roleQuery)
+	rows, err := c.Query(
+		WithParameters(ctx, map[string]string{"name": userName}),
+		"SELECT name, default_roles_list FROM system.users WHERE name = {name:String}",
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching user: %s", err)
 	}
@@ -35,19 +35,22 @@ func (c *Client) CreateUser(ctx context.Context, userPlan models.UserResource) (
 	for _, role := range userPlan.Roles.List() {
 		rolesList = append(rolesList, role.(string))
 	}
-	query := fmt.Sprintf(
-		"CREATE USER %s IDENTIFIED WITH sha256_password BY '%s'",
-		userPlan.Name,
-		userPlan.Password,
-	)
+	statement := common.NewStatement("CREATE USER").
+		Identifier(userPlan.Name).
+		OnCluster(c.GetCluster(userPlan.Cluster)).
+		Raw("IDENTIFIED WITH sha256_password BY").
+		Literal(userPlan.Password)
 
 	if len(rolesList) > 0 {
-		query = fmt.Sprintf("%s DEFAULT ROLE %s", query, strings.Join(rolesList, ","))
+		statement.Raw("DEFAULT ROLE").Identifiers(rolesList)
 	}
-	err := c.Conn.Exec(ctx, query)
+	err := c.Exec(ctx, statement.SQL())
 	if err != nil {
 		return nil, fmt.Errorf("error creating user: %s", err)
 	}
+	if c.DryRun {
+		return &models.CHUser{Name: userPlan.Name, Roles: rolesList}, nil
+	}
 	return c.GetUser(ctx, userPlan.Name)
 }
 
@@ -61,6 +64,8 @@ func (c *Client) UpdateUser(ctx context.Context, userPlan models.UserResource, r
 		return nil, fmt.Errorf("user %s not found", userPlan.Name)
 	}
 
+	cluster := c.GetCluster(userPlan.Cluster)
+
 	userNameHasChange := resourceData.HasChange("name")
 	userPasswordHasChange := resourceData.HasChange("password")
 	userRolesHasChange := resourceData.HasChange("roles")
@@ -88,46 +93,46 @@ func (c *Client) UpdateUser(ctx context.Context, userPlan models.UserResource, r
 	}
 
 	if len(grantRoles) > 0 {
-		err := c.Conn.Exec(ctx, fmt.Sprintf("GRANT %s TO %s", strings.Join(grantRoles, ","), stateUserName))
+		err := c.Exec(ctx, common.NewStatement("GRANT").OnCluster(cluster).Identifiers(grantRoles).Raw("TO").Identifier(stateUserName.(string)).SQL())
 		if err != nil {
 			return nil, fmt.Errorf("error granting roles to user: %s", err)
 		}
 	}
 
 	if len(revokeRoles) > 0 {
-		err := c.Conn.Exec(ctx, fmt.Sprintf("REVOKE %s FROM %s", strings.Join(revokeRoles, ","), stateUserName))
+		err := c.Exec(ctx, common.NewStatement("REVOKE").OnCluster(cluster).Identifiers(revokeRoles).Raw("FROM").Identifier(stateUserName.(string)).SQL())
 		if err != nil {
 			return nil, fmt.Errorf("error revoking roles from user: %s", err)
 		}
 	}
 
-	var changeNameClause string
-	var changePasswordClause string
+	statement := common.NewStatement("ALTER USER").Identifier(stateUserName.(string)).OnCluster(cluster)
 
 	if userNameHasChange {
-		changeNameClause = fmt.Sprintf(" RENAME TO %s", userPlan.Name)
+		statement.Raw("RENAME TO").Identifier(userPlan.Name)
 	}
 
 	if userPasswordHasChange {
-		changePasswordClause = fmt.Sprintf(" IDENTIFIED with sha256_password BY '%s'", userPlan.Password)
+		statement.Raw("IDENTIFIED WITH sha256_password BY").Literal(userPlan.Password)
 	}
 
 	// After modify original role grants, we need to update default roles
-	query := fmt.Sprintf(
-		"ALTER USER %s%s%s DEFAULT ROLE %s",
-		stateUserName,
-		changeNameClause,
-		changePasswordClause,
-		strings.Join(common.StringSetToList(userPlan.Roles), ","),
-	)
-	err = c.Conn.Exec(ctx, query)
+	if roles := common.StringSetToList(userPlan.Roles); len(roles) > 0 {
+		statement.Raw("DEFAULT ROLE").Identifiers(roles)
+	} else {
+		statement.Raw("DEFAULT ROLE NONE")
+	}
+	err = c.Exec(ctx, statement.SQL())
 	if err != nil {
 		return nil, fmt.Errorf("error updating user: %s", err)
 	}
+	if c.DryRun {
+		return &models.CHUser{Name: userPlan.Name, Roles: common.StringSetToList(userPlan.Roles)}, nil
+	}
 
 	return c.GetUser(ctx, userPlan.Name)
 }
 
-func (c *Client) DeleteUser(ctx context.Context, name string) error {
-	return c.Conn.Exec(ctx, fmt.Sprintf("DROP USER %s", name))
+func (c *Client) DeleteUser(ctx context.Context, name string, cluster string) error {
+	return c.Exec(ctx, common.NewStatement("DROP USER").Identifier(name).OnCluster(c.GetCluster(cluster)).SQL())
 }
@@ -0,0 +1,31 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+func TestUserStatements(t *testing.T) {
+	conn := sdktest.NewFakeConn()
+	c := NewClientWithConn(conn)
+	c.DefaultCluster = "main"
+
+	user := models.UserResource{
+		Name:     "etl-reader",
+		Password: `it's a \secret`,
+		Roles:    common.StringListToSet([]string{"analytics-reader"}),
+	}
+	if _, err := c.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if err := c.DeleteUser(context.Background(), user.Name, ""); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	sdktest.AssertGolden(t, filepath.Join("testdata", "user_create_delete.sql"), conn.Statements())
+}
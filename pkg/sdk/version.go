@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion is a Clickhouse server version, e.g. 24.3.2.23
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Build int
+}
+
+// ParseServerVersion parses the value returned by `SELECT version()`. Missing components are
+// read as 0 and any suffix (e.g. `-lts`) is ignored.
+func ParseServerVersion(version string) (ServerVersion, error) {
+	version = strings.TrimSpace(version)
+	if index := strings.IndexAny(version, "-+ "); index >= 0 {
+		version = version[:index]
+	}
+
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > 4 {
+		return ServerVersion{}, fmt.Errorf("invalid Clickhouse version %q", version)
+	}
+
+	var components [4]int
+	for i, part := range parts {
+		component, err := strconv.Atoi(part)
+		if err != nil || component < 0 {
+			return ServerVersion{}, fmt.Errorf("invalid Clickhouse version %q", version)
+		}
+		components[i] = component
+	}
+	return ServerVersion{Major: components[0], Minor: components[1], Patch: components[2], Build: components[3]}, nil
+}
+
+// Compare returns -1, 0 or 1 when the version is respectively older than, equal to or newer than the other one
+func (v ServerVersion) Compare(other ServerVersion) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}, {v.Build, other.Build}} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast tells whether the version is the same as or newer than the other one
+func (v ServerVersion) AtLeast(other ServerVersion) bool {
+	return v.Compare(other) >= 0
+}
+
+func (v ServerVersion) String() string {
+	version := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Patch != 0 || v.Build != 0 {
+		version += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.Build != 0 {
+		version += fmt.Sprintf(".%d", v.Build)
+	}
+	return version
+}
+
+// Feature is a DDL form only supported from a given Clickhouse version
+type Feature struct {
+	Name       string
+	MinVersion ServerVersion
+}
+
+// Features depending on the server version, see https://clickhouse.com/docs/en/whats-new/changelog
+var (
+	FeatureModifyComment   = Feature{Name: "ALTER TABLE ... MODIFY COMMENT", MinVersion: ServerVersion{Major: 21, Minor: 9}}
+	FeatureModifyDBComment = Feature{Name: "ALTER DATABASE ... MODIFY COMMENT", MinVersion: ServerVersion{Major: 24, Minor: 12}}
+)
+
+// Check returns an error when the feature isn't supported by the given server version
+func (f Feature) Check(version ServerVersion) error {
+	if !version.AtLeast(f.MinVersion) {
+		return fmt.Errorf("%s requires ClickHouse >= %s, the server runs %s", f.Name, f.MinVersion, version)
+	}
+	return nil
+}
+
+// ServerVersion returns the version of the Clickhouse server, which is only fetched once
+func (c *Client) ServerVersion(ctx context.Context) (ServerVersion, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.serverVersion != nil {
+		return *c.serverVersion, nil
+	}
+
+	var version string
+	if err := c.QueryRow(ctx, "SELECT version()").Scan(&version); err != nil {
+		return ServerVersion{}, fmt.Errorf("reading Clickhouse server version: %w", err)
+	}
+	serverVersion, err := ParseServerVersion(version)
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	c.serverVersion = &serverVersion
+	return serverVersion, nil
+}
@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	testCases := []struct {
+		version     string
+		expected    ServerVersion
+		expectError bool
+	}{
+		{version: "24.3.2.23", expected: ServerVersion{Major: 24, Minor: 3, Patch: 2, Build: 23}},
+		{version: "23.8.9.54-lts", expected: ServerVersion{Major: 23, Minor: 8, Patch: 9, Build: 54}},
+		{version: "22.8", expected: ServerVersion{Major: 22, Minor: 8}},
+		{version: "", expectError: true},
+		{version: "latest", expectError: true},
+		{version: "1.2.3.4.5", expectError: true},
+	}
+
+	for _, tt := range testCases {
+		version, err := ParseServerVersion(tt.version)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseServerVersion(%q) expected an error, got %v", tt.version, version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseServerVersion(%q) unexpected error: %v", tt.version, err)
+		}
+		if version != tt.expected {
+			t.Errorf("ParseServerVersion(%q) = %v, expected %v", tt.version, version, tt.expected)
+		}
+	}
+}
+
+func TestServerVersionCompare(t *testing.T) {
+	testCases := []struct {
+		version  ServerVersion
+		other    ServerVersion
+		expected int
+	}{
+		{version: ServerVersion{Major: 23, Minor: 3}, other: ServerVersion{Major: 23, Minor: 3}, expected: 0},
+		{version: ServerVersion{Major: 23, Minor: 12}, other: ServerVersion{Major: 23, Minor: 3}, expected: 1},
+		{version: ServerVersion{Major: 22, Minor: 12}, other: ServerVersion{Major: 23, Minor: 3}, expected: -1},
+		{version: ServerVersion{Major: 23, Minor: 3, Patch: 1}, other: ServerVersion{Major: 23, Minor: 3}, expected: 1},
+	}
+
+	for _, tt := range testCases {
+		if result := tt.version.Compare(tt.other); result != tt.expected {
+			t.Errorf("%v.Compare(%v) = %d, expected %d", tt.version, tt.other, result, tt.expected)
+		}
+	}
+}
+
+func TestFeatureCheck(t *testing.T) {
+	if err := FeatureModifyComment.Check(ServerVersion{Major: 21, Minor: 9, Patch: 1, Build: 2}); err != nil {
+		t.Errorf("expected MODIFY COMMENT to be supported by 21.9.1.2, got %v", err)
+	}
+
+	err := FeatureModifyComment.Check(ServerVersion{Major: 21, Minor: 8, Patch: 5})
+	expected := "ALTER TABLE ... MODIFY COMMENT requires ClickHouse >= 21.9, the server runs 21.8.5"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
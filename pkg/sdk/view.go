@@ -4,14 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 )
 
 func (c *Client) GetView(ctx context.Context, database string, view string) (*models.CHView, error) {
-	query := fmt.Sprintf("SELECT database, name, engine, as_select, comment FROM system.tables where database = '%s' and name = '%s'", database, view)
-	row := c.Conn.QueryRow(ctx, query)
+	row := c.QueryRow(
+		WithParameters(ctx, map[string]string{"database": database, "name": view}),
+		"SELECT database, name, engine, as_select, comment FROM system.tables WHERE database = {database:String} AND name = {name:String}",
+	)
 
 	if row.Err() != nil {
 		return nil, fmt.Errorf("reading view from Clickhouse: %v", row.Err())
@@ -33,8 +36,25 @@ func (c *Client) GetView(ctx context.Context, database string, view string) (*mo
 }
 
 func (c *Client) CreateView(ctx context.Context, resource models.ViewResource) error {
+	resource.Cluster = c.GetCluster(resource.Cluster)
+	if resource.CreateMode == common.CreateModeAdopt {
+		existing, err := c.GetView(ctx, resource.Database, resource.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			existingResource, err := existing.ToResource()
+			if err != nil {
+				return err
+			}
+			if differences := resource.Differences(existingResource); len(differences) > 0 {
+				return fmt.Errorf("view %s.%s already exists and differs from the configuration: %s", resource.Database, resource.Name, strings.Join(differences, ", "))
+			}
+			return nil
+		}
+	}
 	query := buildCreateOnClusterSentence(resource)
-	err := c.Conn.Exec(ctx, query)
+	err := c.Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("creating Clickhouse view: %v", err)
 	}
@@ -42,8 +62,11 @@ func (c *Client) CreateView(ctx context.Context, resource models.ViewResource) e
 }
 
 func (c *Client) DeleteView(ctx context.Context, resource models.ViewResource) error {
-	query := fmt.Sprintf("DROP VIEW if exists %s.%s %s", resource.Database, resource.Name, common.GetClusterStatement(resource.Cluster))
-	err := c.Conn.Exec(ctx, query)
+	query := common.NewStatement("DROP VIEW IF EXISTS").
+		QualifiedName(resource.Database, resource.Name).
+		OnCluster(c.GetCluster(resource.Cluster)).
+		SQL()
+	err := c.Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("deleting Clickhouse view: %v", err)
 	}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/sdk/sdktest"
+)
+
+func TestCreateViewStatements(t *testing.T) {
+	testCases := []struct {
+		name string
+		view models.ViewResource
+	}{
+		{
+			name: "view",
+			view: models.ViewResource{
+				Database: "analytics",
+				Name:     "daily_events",
+				Query:    "SELECT event_date, count() FROM analytics.events GROUP BY event_date",
+				Comment:  "Events per day",
+			},
+		},
+		{
+			name: "materialized_on_cluster",
+			view: models.ViewResource{
+				Database:     "analytics",
+				Name:         "daily_events_mv",
+				Cluster:      "main",
+				Materialized: true,
+				ToTable:      "analytics.daily_events",
+				Query:        "SELECT event_date, count() AS events FROM analytics.events GROUP BY event_date",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := sdktest.NewFakeConn()
+			c := NewClientWithConn(conn)
+
+			if err := c.CreateView(context.Background(), tc.view); err != nil {
+				t.Fatalf("CreateView() error = %v", err)
+			}
+			if err := c.DeleteView(context.Background(), tc.view); err != nil {
+				t.Fatalf("DeleteView() error = %v", err)
+			}
+
+			sdktest.AssertGolden(t, filepath.Join("testdata", "create_view_"+tc.name+".sql"), conn.Statements())
+		})
+	}
+}
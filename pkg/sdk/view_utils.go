@@ -2,25 +2,27 @@ package sdk
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
 	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
 )
 
 func buildCreateOnClusterSentence(resource models.ViewResource) (query string) {
-	clusterStatement := common.GetClusterStatement(resource.Cluster)
+	// The deprecated create statement env vars never applied to views
+	createMode := resource.CreateMode
+	if createMode == "" {
+		createMode = common.CreateModeCreate
+	}
+	createStatement := common.GetCreateStatement(strings.TrimSpace(isMaterializedStatement(resource.Materialized)+" VIEW"), createMode)
 
-	ret := fmt.Sprintf(
-		"CREATE %s VIEW %v.%v %v %s as (%s) COMMENT '%s'",
-		isMaterializedStatement(resource.Materialized),
-		resource.Database,
-		resource.Name,
-		clusterStatement,
-		toTableStatement(resource.ToTable),
-		resource.Query,
-		resource.Comment,
-	)
-	return ret
+	return common.NewStatement(createStatement).
+		QualifiedName(resource.Database, resource.Name).
+		OnCluster(resource.Cluster).
+		Raw(toTableStatement(resource.ToTable)).
+		Raw(fmt.Sprintf("AS (%s)", resource.Query)).
+		Comment(resource.Comment).
+		SQL()
 }
 
 func isMaterializedStatement(materialized bool) string {
@@ -0,0 +1,48 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/common"
+	"github.com/FlowdeskMarkets/terraform-provider-clickhouse/pkg/models"
+)
+
+func TestBuildCreateViewSentence(t *testing.T) {
+	testCases := []struct {
+		materialized bool
+		createMode   string
+		expectedSQL  string
+	}{
+		{
+			expectedSQL: "CREATE VIEW `db`.`v` AS (select 1)",
+		},
+		{
+			createMode:  common.CreateModeOrReplace,
+			expectedSQL: "CREATE OR REPLACE VIEW `db`.`v` AS (select 1)",
+		},
+		{
+			materialized: true,
+			createMode:   common.CreateModeIfNotExists,
+			expectedSQL:  "CREATE MATERIALIZED VIEW IF NOT EXISTS `db`.`v` AS (select 1)",
+		},
+		{
+			materialized: true,
+			createMode:   common.CreateModeOrReplace,
+			expectedSQL:  "CREATE MATERIALIZED VIEW `db`.`v` AS (select 1)",
+		},
+	}
+
+	for _, tt := range testCases {
+		query := buildCreateOnClusterSentence(models.ViewResource{
+			Database:     "db",
+			Name:         "v",
+			Query:        "select 1",
+			Materialized: tt.materialized,
+			CreateMode:   tt.createMode,
+		})
+		if query = strings.Join(strings.Fields(query), " "); query != tt.expectedSQL {
+			t.Errorf("buildCreateOnClusterSentence() = %q, expected %q", query, tt.expectedSQL)
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package testutils
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
@@ -28,6 +30,18 @@ func Provider() map[string]*schema.Provider {
 	return TestAccProviders
 }
 
+// ProtoV6ProviderFactories serve the muxed provider, built around TestAccProvider so that its
+// client can be used to check the server state
+var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"clickhouse": func() (tfprotov6.ProviderServer, error) {
+		muxServer, err := provider.MuxServer(context.Background(), TestAccProvider, "dev")
+		if err != nil {
+			return nil, err
+		}
+		return muxServer(), nil
+	},
+}
+
 func TestAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -87,6 +101,7 @@ func CheckStateSetAttr(attrKey string, resource string, expectedItems []string)
 
 type TestCase struct {
 	EnvVars     map[string]string
+	CreateMode  string
 	ExpectedSQL string
 }
 
@@ -98,7 +113,7 @@ func RunGetCreateStatementTest(t *testing.T, resourceType string, testCases []Te
 			}
 		}
 
-		result := common.GetCreateStatement(resourceType)
+		result := common.GetCreateStatement(resourceType, tt.CreateMode)
 
 		if result != tt.ExpectedSQL {
 			t.Errorf("GetCreateStatement() = %v, expected %v", result, tt.ExpectedSQL)